@@ -0,0 +1,110 @@
+// Команда canonicalize пересчитывает canonical_url для ссылок, созданных до миграции 008:
+// миграция заполнила столбец исходным адресом, и такие ссылки не находились как дубли.
+// Запускается один раз после миграции с той же конфигурацией, что и сервер
+// (URL_STRIP_FRAGMENT, URL_TRACKING_PARAMS); повторный запуск ничего не меняет.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/infrastructure/db"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"github.com/Thoustick/SlugKiller/pkg/urlnorm"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// batchSize — сколько ссылок читается за один запрос.
+const batchSize = 1000
+
+type link struct {
+	id        int64
+	url       string
+	canonical string
+}
+
+func main() {
+	cfg := config.Load()
+	log := logger.InitLogger(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pg, err := db.New(ctx, cfg.DatabaseURL, cfg.DBTimeout, log)
+	if err != nil {
+		log.Fatal("failed to connect to database", err, nil)
+	}
+	defer pg.Close()
+
+	opts := urlnorm.Options{
+		StripFragment:  cfg.URLStripFragment,
+		TrackingParams: cfg.URLTrackingParams,
+	}
+	scanned, updated, err := canonicalize(ctx, pg.Pool, opts)
+	if err != nil {
+		pg.Close()
+		log.Fatal("canonical URL backfill failed", err, map[string]interface{}{
+			"scanned": scanned,
+			"updated": updated,
+		})
+	}
+	log.Info("canonical URL backfill finished", map[string]interface{}{
+		"scanned": scanned,
+		"updated": updated,
+	})
+}
+
+// canonicalize обходит ссылки по возрастанию id и обновляет те, у которых canonical_url
+// отличается от нормализованного адреса. Адрес, который не удаётся нормализовать,
+// остаётся как есть — так же поступает сервис при создании ссылки.
+func canonicalize(ctx context.Context, pool *pgxpool.Pool, opts urlnorm.Options) (scanned, updated int, err error) {
+	var lastID int64
+	for {
+		batch, err := readBatch(ctx, pool, lastID)
+		if err != nil {
+			return scanned, updated, err
+		}
+		if len(batch) == 0 {
+			return scanned, updated, nil
+		}
+
+		for _, l := range batch {
+			canonical, err := urlnorm.Normalize(l.url, opts)
+			if err != nil {
+				canonical = l.url
+			}
+			if canonical == l.canonical {
+				continue
+			}
+			if _, err := pool.Exec(ctx, `UPDATE urls SET canonical_url = $2 WHERE id = $1`, l.id, canonical); err != nil {
+				return scanned, updated, fmt.Errorf("update link %d: %w", l.id, err)
+			}
+			updated++
+		}
+		scanned += len(batch)
+		lastID = batch[len(batch)-1].id
+	}
+}
+
+func readBatch(ctx context.Context, pool *pgxpool.Pool, afterID int64) ([]link, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT id, url, canonical_url FROM urls WHERE id > $1 ORDER BY id LIMIT $2`,
+		afterID, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("select links: %w", err)
+	}
+	defer rows.Close()
+
+	batch := make([]link, 0, batchSize)
+	for rows.Next() {
+		var l link
+		if err := rows.Scan(&l.id, &l.url, &l.canonical); err != nil {
+			return nil, fmt.Errorf("scan link: %w", err)
+		}
+		batch = append(batch, l)
+	}
+	return batch, rows.Err()
+}
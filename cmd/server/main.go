@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"os/signal"
+	"syscall"
 
 	"github.com/Thoustick/SlugKiller/config"
 	"github.com/Thoustick/SlugKiller/internal/di"
@@ -9,8 +11,15 @@ import (
 
 func main() {
 	cfg := config.Load()
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// SIGINT/SIGTERM отменяют контекст приложения и запускают корректную остановку
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// После первого сигнала возвращаем стандартную обработку: повторный сигнал завершит процесс сразу
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 
 	app, err := di.NewApp(
 		ctx,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Thoustick/SlugKiller/internal/tracing"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -19,7 +20,14 @@ func New(ctx context.Context, connString string, timeOut time.Duration, log logg
 	ctxWTO, cancel := context.WithTimeout(ctx, timeOut)
 	defer cancel()
 
-	pool, err := pgxpool.New(ctxWTO, connString)
+	poolCfg, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("pgxpool.ParseConfig: %w", err)
+	}
+	// Каждый запрос к базе получает свой спан
+	poolCfg.ConnConfig.Tracer = tracing.PgxTracer{}
+
+	pool, err := pgxpool.NewWithConfig(ctxWTO, poolCfg)
 	if err != nil {
 		log.Error("failed to connect to database", err, map[string]interface{}{
 			"dsn": connString,
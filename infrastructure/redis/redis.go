@@ -51,3 +51,19 @@ func NewRedisClient(redisURL, password string, dbIndex int, l logger.Logger) (*C
 func (r *Client) Client() *redis.Client {
 	return r.client
 }
+
+// Ping проверяет соединение с Redis.
+func (r *Client) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// PoolStats возвращает статистику пула соединений.
+func (r *Client) PoolStats() *redis.PoolStats {
+	return r.client.PoolStats()
+}
+
+// Close закрывает пул соединений.
+func (r *Client) Close() error {
+	r.logger.Info("closing Redis connection", nil)
+	return r.client.Close()
+}
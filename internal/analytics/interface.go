@@ -0,0 +1,18 @@
+package analytics
+
+import "time"
+
+// ClickEvent — сырое событие перехода по ссылке в том виде, в каком его видит HTTP-слой.
+type ClickEvent struct {
+	Slug      string
+	At        time.Time
+	Referrer  string
+	UserAgent string
+	ClientIP  string
+	Variant   string // вариант A/B-разбиения; пусто — разбиения не было
+}
+
+// ClickRecorder принимает события переходов. Реализация не должна блокировать вызывающего.
+type ClickRecorder interface {
+	Record(event ClickEvent)
+}
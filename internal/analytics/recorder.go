@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"github.com/Thoustick/SlugKiller/pkg/useragent"
+)
+
+// Recorder складывает события в ограниченную очередь и пачками пишет их в хранилище
+// из фоновой горутины. Переполнение очереди не блокирует редирект: событие отбрасывается
+// и учитывается в счётчике Dropped.
+type Recorder struct {
+	queue         chan ClickEvent
+	writer        repository.ClickWriter
+	batchSize     int
+	flushInterval time.Duration
+	writeTimeout  time.Duration
+	ipSalt        string
+	logger        logger.Logger
+
+	dropped  atomic.Uint64
+	reported uint64 // сколько потерь уже попало в лог; меняется только воркером
+}
+
+var _ ClickRecorder = (*Recorder)(nil)
+
+// Значения по умолчанию для незаполненных полей конфигурации
+const (
+	defaultQueueSize     = 1000
+	defaultFlushInterval = time.Second
+	defaultWriteTimeout  = 5 * time.Second
+)
+
+func NewRecorder(w repository.ClickWriter, cfg *config.Config, log logger.Logger) *Recorder {
+	return &Recorder{
+		queue:         make(chan ClickEvent, positiveOr(cfg.ClickQueueSize, defaultQueueSize)),
+		writer:        w,
+		batchSize:     max(cfg.ClickBatchSize, 1),
+		flushInterval: positiveOr(cfg.ClickFlushInterval, defaultFlushInterval),
+		writeTimeout:  positiveOr(cfg.DBTimeout, defaultWriteTimeout),
+		ipSalt:        cfg.ClickIPSalt,
+		logger:        log,
+	}
+}
+
+func positiveOr[T int | time.Duration](v, fallback T) T {
+	if v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// Record ставит событие в очередь без ожидания.
+func (r *Recorder) Record(event ClickEvent) {
+	select {
+	case r.queue <- event:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped возвращает число событий, потерянных из-за переполнения очереди.
+func (r *Recorder) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// QueueLen возвращает текущее число событий в очереди.
+func (r *Recorder) QueueLen() int {
+	return len(r.queue)
+}
+
+// Run обрабатывает очередь до отмены ctx, после чего дописывает оставшиеся события и возвращается.
+func (r *Recorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.Click, 0, r.batchSize)
+	for {
+		select {
+		case event := <-r.queue:
+			batch = r.add(batch, event)
+		case <-ticker.C:
+			batch = r.flush(batch)
+			r.reportDropped()
+		case <-ctx.Done():
+			r.drain(batch)
+			return
+		}
+	}
+}
+
+// drain дочитывает очередь после остановки и сбрасывает всё в хранилище.
+func (r *Recorder) drain(batch []model.Click) {
+	for {
+		select {
+		case event := <-r.queue:
+			batch = r.add(batch, event)
+		default:
+			r.flush(batch)
+			r.reportDropped()
+			return
+		}
+	}
+}
+
+func (r *Recorder) add(batch []model.Click, event ClickEvent) []model.Click {
+	batch = append(batch, model.Click{
+		Slug:      event.Slug,
+		ClickedAt: event.At,
+		Referrer:  event.Referrer,
+		UserAgent: event.UserAgent,
+		UAFamily:  useragent.Family(event.UserAgent),
+		IPHash:    HashIP(event.ClientIP, r.ipSalt),
+		Variant:   event.Variant,
+	})
+	if len(batch) >= r.batchSize {
+		return r.flush(batch)
+	}
+	return batch
+}
+
+// flush пишет пачку в хранилище и возвращает опустошённый срез для повторного использования.
+func (r *Recorder) flush(batch []model.Click) []model.Click {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.writeTimeout)
+	defer cancel()
+
+	if err := r.writer.SaveClicks(ctx, batch); err != nil {
+		r.logger.Error("Failed to save clicks", err, map[string]interface{}{
+			"count": len(batch),
+		})
+	}
+	return batch[:0]
+}
+
+func (r *Recorder) reportDropped() {
+	dropped := r.dropped.Load()
+	if dropped == r.reported {
+		return
+	}
+	r.logger.Warn("Click queue overflow, events dropped", map[string]interface{}{
+		"dropped_since_last_report": dropped - r.reported,
+		"dropped_total":             dropped,
+	})
+	r.reported = dropped
+}
+
+// HashIP возвращает необратимый хеш IP-адреса с солью.
+func HashIP(ip, salt string) string {
+	if ip == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(salt + ip))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/analytics"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func newLogger() *mocks.MockLogger {
+	log := new(mocks.MockLogger)
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return log
+}
+
+func TestRecorder_FlushesFullBatch(t *testing.T) {
+	store := mem.NewClickStore()
+	cfg := &config.Config{ClickQueueSize: 10, ClickBatchSize: 2, ClickFlushInterval: time.Hour}
+	rec := analytics.NewRecorder(store, cfg, newLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rec.Run(ctx)
+
+	rec.Record(analytics.ClickEvent{Slug: "abc", At: time.Now(), ClientIP: "10.0.0.1"})
+	rec.Record(analytics.ClickEvent{Slug: "abc", At: time.Now(), ClientIP: "10.0.0.2"})
+
+	// Пачка заполнена — запись не ждёт тикера
+	require.Eventually(t, func() bool { return len(store.Clicks("abc")) == 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestRecorder_DrainsQueueOnShutdown(t *testing.T) {
+	store := mem.NewClickStore()
+	cfg := &config.Config{ClickQueueSize: 10, ClickBatchSize: 100, ClickFlushInterval: time.Hour}
+	rec := analytics.NewRecorder(store, cfg, newLogger())
+
+	for i := 0; i < 3; i++ {
+		rec.Record(analytics.ClickEvent{Slug: "abc", At: time.Now(), Referrer: "https://news.example", Variant: "b"})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec.Run(ctx) // уже отменён: должен дописать очередь и вернуться
+
+	clicks := store.Clicks("abc")
+	assert.Len(t, clicks, 3)
+	assert.Equal(t, "https://news.example", clicks[0].Referrer)
+	assert.Equal(t, "b", clicks[0].Variant)
+	assert.Zero(t, rec.QueueLen())
+}
+
+func TestRecorder_OverflowIsCountedNotBlocking(t *testing.T) {
+	writer := new(mocks.MockStore)
+	cfg := &config.Config{ClickQueueSize: 2, ClickBatchSize: 10, ClickFlushInterval: time.Hour}
+	rec := analytics.NewRecorder(writer, cfg, newLogger())
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			rec.Record(analytics.ClickEvent{Slug: "abc"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full queue")
+	}
+	assert.Equal(t, uint64(3), rec.Dropped())
+	assert.Equal(t, 2, rec.QueueLen())
+}
+
+func TestRecorder_HashesClientIP(t *testing.T) {
+	writer := new(mocks.MockStore)
+	cfg := &config.Config{ClickQueueSize: 1, ClickBatchSize: 1, ClickFlushInterval: time.Hour, ClickIPSalt: "pepper"}
+	rec := analytics.NewRecorder(writer, cfg, newLogger())
+
+	writer.On("SaveClicks", mock.Anything, mock.MatchedBy(func(clicks []model.Click) bool {
+		return len(clicks) == 1 &&
+			clicks[0].IPHash == analytics.HashIP("192.0.2.7", "pepper") &&
+			clicks[0].IPHash != "192.0.2.7"
+	})).Return(nil).Once()
+
+	rec.Record(analytics.ClickEvent{Slug: "abc", ClientIP: "192.0.2.7"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec.Run(ctx)
+
+	writer.AssertExpectations(t)
+}
+
+func TestHashIP(t *testing.T) {
+	assert.Equal(t, analytics.HashIP("1.2.3.4", "s"), analytics.HashIP("1.2.3.4", "s"))
+	assert.NotEqual(t, analytics.HashIP("1.2.3.4", "s"), analytics.HashIP("1.2.3.4", "other"))
+	assert.Len(t, analytics.HashIP("1.2.3.4", ""), 64)
+	assert.Empty(t, analytics.HashIP("", "s"))
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// HashKey возвращает SHA-256 ключа в hex — в таком виде ключ хранится и ищется.
+// Медленный хеш не нужен: ключи случайные и длинные, перебор по словарю им не грозит.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Seed регистрирует ключи из конфигурации в формате «владелец:ключ». Уже известные ключи пропускаются,
+// поэтому повторный запуск с тем же списком безопасен.
+func Seed(ctx context.Context, store repository.APIKeyStore, entries []string) error {
+	for _, entry := range entries {
+		owner, key, ok := strings.Cut(entry, ":")
+		owner, key = strings.TrimSpace(owner), strings.TrimSpace(key)
+		if !ok || owner == "" || key == "" {
+			return fmt.Errorf("invalid API key entry %q: want owner:key", maskEntry(entry))
+		}
+
+		err := store.CreateAPIKey(ctx, &model.APIKey{OwnerID: owner, Name: "config", KeyHash: HashKey(key)})
+		if err != nil && !errors.Is(err, repository.ErrAlreadyExists) {
+			return fmt.Errorf("seed API key for %s: %w", owner, err)
+		}
+	}
+	return nil
+}
+
+// maskEntry скрывает ключ в сообщении об ошибке, оставляя владельца.
+func maskEntry(entry string) string {
+	owner, _, ok := strings.Cut(entry, ":")
+	if !ok {
+		return "***"
+	}
+	return owner + ":***"
+}
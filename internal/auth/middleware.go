@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// apiKeyContextKey — ключ, под которым найденный API-ключ кладётся в gin.Context.
+const apiKeyContextKey = "auth.api_key"
+
+// Middleware проверяет API-ключ из заголовка «Authorization: Bearer <ключ>» или «X-API-Key».
+// При required запрос без ключа отклоняется с 401; иначе он проходит анонимно.
+// Неверный или отозванный ключ отклоняется всегда, даже если анонимный доступ разрешён.
+func Middleware(keys repository.APIKeyStore, required bool, log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := KeyFromRequest(c.Request)
+		if raw == "" {
+			if required {
+				unauthorized(c, "API key required")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		key, err := keys.GetAPIKeyByHash(c.Request.Context(), HashKey(raw))
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			log.Warn("Unknown API key", map[string]interface{}{
+				"ip":   c.ClientIP(),
+				"path": c.Request.URL.Path,
+			})
+			unauthorized(c, "Invalid API key")
+			return
+		case errors.Is(err, repository.ErrUnavailable):
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Service temporarily unavailable"})
+			return
+		case err != nil:
+			log.Error("Failed to look up API key", err, nil)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+			return
+		}
+
+		if key.IsRevoked() {
+			log.Warn("Revoked API key used", map[string]interface{}{
+				"key_id":   key.ID,
+				"owner_id": key.OwnerID,
+				"ip":       c.ClientIP(),
+			})
+			unauthorized(c, "Invalid API key")
+			return
+		}
+
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// OwnerID возвращает владельца ключа, с которым пришёл запрос; пусто — запрос анонимный.
+func OwnerID(c *gin.Context) string {
+	if key, ok := c.Get(apiKeyContextKey); ok {
+		return key.(*model.APIKey).OwnerID
+	}
+	return ""
+}
+
+// KeyFromRequest достаёт ключ из Authorization (схема Bearer) или X-API-Key.
+func KeyFromRequest(r *http.Request) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, ok := strings.Cut(header, " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+func unauthorized(c *gin.Context, msg string) {
+	c.Header("WWW-Authenticate", `Bearer realm="slugkiller"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": msg})
+}
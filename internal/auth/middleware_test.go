@@ -0,0 +1,113 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+// setupRouter отвечает владельцем ключа, с которым пришёл запрос.
+func setupRouter(t *testing.T, required bool) (*gin.Engine, *mem.APIKeyStore) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := mem.NewAPIKeyStore()
+	log := &mocks.MockLogger{}
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	r := gin.New()
+	r.GET("/", auth.Middleware(store, required, log), func(c *gin.Context) {
+		c.String(http.StatusOK, auth.OwnerID(c))
+	})
+	return r, store
+}
+
+func request(r *gin.Engine, header, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ValidKey(t *testing.T) {
+	r, store := setupRouter(t, true)
+	require.NoError(t, auth.Seed(context.Background(), store, []string{"team-a:secret"}))
+
+	for header, value := range map[string]string{
+		"Authorization": "Bearer secret",
+		"X-API-Key":     "secret",
+	} {
+		w := request(r, header, value)
+		assert.Equal(t, http.StatusOK, w.Code, header)
+		assert.Equal(t, "team-a", w.Body.String(), header)
+	}
+}
+
+func TestMiddleware_MissingKey(t *testing.T) {
+	r, _ := setupRouter(t, true)
+	w := request(r, "", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	// Без обязательного ключа запрос проходит анонимно
+	r, _ = setupRouter(t, false)
+	w = request(r, "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestMiddleware_RejectsInvalidKeyEvenIfOptional(t *testing.T) {
+	r, store := setupRouter(t, false)
+	revokedAt := time.Now()
+	require.NoError(t, store.CreateAPIKey(context.Background(), &model.APIKey{
+		OwnerID:   "team-a",
+		KeyHash:   auth.HashKey("revoked"),
+		RevokedAt: &revokedAt,
+	}))
+
+	assert.Equal(t, http.StatusUnauthorized, request(r, "X-API-Key", "unknown").Code)
+	assert.Equal(t, http.StatusUnauthorized, request(r, "Authorization", "Bearer revoked").Code)
+}
+
+func TestMiddleware_StoreUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &mocks.MockStore{}
+	store.On("GetAPIKeyByHash", mock.Anything, auth.HashKey("secret")).Return(nil, repository.ErrUnavailable)
+
+	r := gin.New()
+	r.GET("/", auth.Middleware(store, true, &mocks.MockLogger{}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	assert.Equal(t, http.StatusServiceUnavailable, request(r, "X-API-Key", "secret").Code)
+}
+
+func TestSeed(t *testing.T) {
+	store := mem.NewAPIKeyStore()
+	ctx := context.Background()
+
+	require.NoError(t, auth.Seed(ctx, store, []string{"team-a:one", " team-b : two "}))
+	// Повторный запуск с теми же ключами не считается ошибкой
+	require.NoError(t, auth.Seed(ctx, store, []string{"team-a:one"}))
+
+	key, err := store.GetAPIKeyByHash(ctx, auth.HashKey("two"))
+	require.NoError(t, err)
+	assert.Equal(t, "team-b", key.OwnerID)
+
+	err = auth.Seed(ctx, store, []string{"no-separator-secret"})
+	assert.EqualError(t, err, `invalid API key entry "***": want owner:key`)
+}
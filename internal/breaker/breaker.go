@@ -0,0 +1,196 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// ErrOpen возвращается, когда breaker не пропускает вызов к зависимости.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State — состояние breaker.
+type State int
+
+const (
+	Closed   State = iota // вызовы идут как обычно, ошибки подряд считаются
+	Open                  // вызовы отклоняются сразу до окончания паузы
+	HalfOpen              // пропускается один пробный вызов; его исход решает, закрыться или снова открыться
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Значения по умолчанию для незаполненных настроек
+const (
+	defaultThreshold = 5
+	defaultCoolDown  = 5 * time.Second
+)
+
+// Settings задаёт чувствительность breaker.
+type Settings struct {
+	Threshold int           // сколько ошибок подряд открывают breaker
+	CoolDown  time.Duration // сколько breaker остаётся открытым перед пробным вызовом
+}
+
+// Stats — накопленные показатели breaker.
+type Stats struct {
+	State    State
+	Opens    uint64 // сколько раз breaker открывался
+	Rejected uint64 // сколько вызовов отклонено без обращения к зависимости
+}
+
+// Breaker — автомат closed → open → half-open. Вызывающий спрашивает Allow перед обращением
+// к зависимости и сообщает исход через Success или Failure.
+type Breaker struct {
+	name     string
+	settings Settings
+	logger   logger.Logger
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool // пробный вызов в half-open уже выполняется
+
+	opens    atomic.Uint64
+	rejected atomic.Uint64
+}
+
+// New создаёт breaker; name попадает в логи и метрики. Незаполненные настройки заменяются
+// значениями по умолчанию.
+func New(name string, settings Settings, log logger.Logger) *Breaker {
+	if settings.Threshold <= 0 {
+		settings.Threshold = defaultThreshold
+	}
+	if settings.CoolDown <= 0 {
+		settings.CoolDown = defaultCoolDown
+	}
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		logger:   log,
+		now:      time.Now,
+	}
+}
+
+// Name возвращает имя breaker.
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow решает, можно ли обратиться к зависимости. После паузы открытый breaker переходит
+// в half-open и пропускает ровно один вызов; остальные получают ErrOpen, пока тот не завершится.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Closed:
+		return nil
+	case Open:
+		if b.now().Sub(b.openedAt) < b.settings.CoolDown {
+			break
+		}
+		b.transition(HalfOpen, nil)
+		b.probing = true
+		return nil
+	case HalfOpen:
+		if !b.probing {
+			b.probing = true
+			return nil
+		}
+	}
+	b.rejected.Add(1)
+	return ErrOpen
+}
+
+// Success сообщает об успешном вызове.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != Closed {
+		b.transition(Closed, nil)
+	}
+}
+
+// Failure сообщает о сбое зависимости. Ошибки, за которые зависимость не отвечает
+// (нет записи, отмена запроса клиентом), сообщать не нужно — для них вызывается Success или Release.
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	b.failures++
+	if b.state == HalfOpen || (b.state == Closed && b.failures >= b.settings.Threshold) {
+		b.openedAt = b.now()
+		b.opens.Add(1)
+		b.transition(Open, err)
+	}
+}
+
+// Release завершает вызов, исход которого о здоровье зависимости ничего не говорит.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State возвращает текущее состояние.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Stats возвращает текущее состояние и счётчики.
+func (b *Breaker) Stats() Stats {
+	return Stats{
+		State:    b.State(),
+		Opens:    b.opens.Load(),
+		Rejected: b.rejected.Load(),
+	}
+}
+
+// transition меняет состояние и пишет переход в лог. Вызывается под мьютексом.
+func (b *Breaker) transition(to State, cause error) {
+	from := b.state
+	b.state = to
+	fields := map[string]interface{}{
+		"breaker": b.name,
+		"from":    from.String(),
+		"to":      to.String(),
+	}
+
+	switch to {
+	case Open:
+		fields["failures"] = b.failures
+		fields["cool_down"] = b.settings.CoolDown.String()
+		if cause != nil {
+			fields["error"] = cause.Error()
+		}
+		b.logger.Warn("Circuit breaker opened", fields)
+	case HalfOpen:
+		b.logger.Info("Circuit breaker half-open, probing", fields)
+	case Closed:
+		b.failures = 0
+		b.logger.Info("Circuit breaker closed", fields)
+	}
+}
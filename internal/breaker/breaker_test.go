@@ -0,0 +1,78 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+func newTestBreaker(threshold int, coolDown time.Duration) (*Breaker, *time.Time) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	b := New("test", Settings{Threshold: threshold, CoolDown: coolDown}, logger.InitLogger(&config.Config{LogLevel: "error"}))
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+	boom := errors.New("boom")
+
+	// Успех между ошибками сбрасывает счётчик
+	b.Failure(boom)
+	b.Failure(boom)
+	b.Success()
+	b.Failure(boom)
+	b.Failure(boom)
+	assert.Equal(t, Closed, b.State())
+	assert.NoError(t, b.Allow())
+
+	b.Failure(boom)
+	assert.Equal(t, Open, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+	assert.Equal(t, Stats{State: Open, Opens: 1, Rejected: 1}, b.Stats())
+}
+
+func TestBreaker_HalfOpenProbe(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	boom := errors.New("boom")
+
+	b.Failure(boom)
+	*now = now.Add(30 * time.Second)
+	assert.ErrorIs(t, b.Allow(), ErrOpen, "пауза ещё не истекла")
+
+	// После паузы проходит ровно один пробный вызов
+	*now = now.Add(30 * time.Second)
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, HalfOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	// Неудачная проба снова открывает breaker на полную паузу
+	b.Failure(boom)
+	assert.Equal(t, Open, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	*now = now.Add(time.Minute)
+	assert.NoError(t, b.Allow())
+	b.Success()
+	assert.Equal(t, Closed, b.State())
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, uint64(2), b.Stats().Opens)
+}
+
+func TestBreaker_ReleaseFreesProbe(t *testing.T) {
+	b, now := newTestBreaker(1, time.Second)
+
+	b.Failure(errors.New("boom"))
+	*now = now.Add(time.Second)
+	assert.NoError(t, b.Allow())
+
+	// Проба отменена клиентом: состояние не меняется, но следующий вызов может стать пробой
+	b.Release()
+	assert.Equal(t, HalfOpen, b.State())
+	assert.NoError(t, b.Allow())
+}
@@ -4,3 +4,7 @@ package cache
 import "errors"
 
 var ErrCacheMiss = errors.New("cache miss") // Добавляем кастомную ошибку для "ключ не найден"
+
+// NotFound — значение-метка «slug не существует» для негативного кэширования.
+// Настоящий адрес всегда абсолютный URL, поэтому спутать их нельзя.
+const NotFound = "\x00not-found"
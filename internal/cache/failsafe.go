@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+)
+
+// FailSafeCache превращает недоступность кэша в промахи: сервис идёт в базу, а не получает ошибку.
+// Сбои считает circuit breaker: пока он открыт, кэш не опрашивается вовсе, чтобы запросы
+// не ждали таймаутов сети. Переходы состояния логирует сам breaker.
+//
+// Удаление терять нельзя: без него отключённая или изменённая ссылка продолжит отдаваться из Redis,
+// а запись в Redis может жить без TTL. Поэтому slug, который не удалось удалить, запоминается,
+// и удаление повторяется перед следующим обращением к Redis, пока оно не пройдёт.
+type FailSafeCache struct {
+	next    URLCache
+	breaker *breaker.Breaker
+
+	mu      sync.Mutex
+	pending map[string]uint64 // slug → номер последнего запроса на удаление
+	seq     uint64
+}
+
+var _ URLCache = (*FailSafeCache)(nil)
+
+func NewFailSafeCache(next URLCache, b *breaker.Breaker) *FailSafeCache {
+	return &FailSafeCache{next: next, breaker: b, pending: make(map[string]uint64)}
+}
+
+func (c *FailSafeCache) Get(ctx context.Context, slug string) (string, error) {
+	if c.breaker.Allow() != nil {
+		return "", ErrCacheMiss
+	}
+	if c.flush(ctx) != nil {
+		return "", ErrCacheMiss
+	}
+	url, err := c.next.Get(ctx, slug)
+	if c.record(err) != nil {
+		return "", ErrCacheMiss
+	}
+	return url, nil
+}
+
+func (c *FailSafeCache) SetNX(ctx context.Context, slug, url string, ttl time.Duration) error {
+	if c.breaker.Allow() != nil {
+		return nil
+	}
+	if c.flush(ctx) != nil {
+		return nil
+	}
+	c.record(c.next.SetNX(ctx, slug, url, ttl))
+	return nil
+}
+
+// Delete возвращает ошибку Redis (или breaker.ErrOpen, пока breaker открыт), но slug не забывает:
+// удаление повторится перед следующим обращением к Redis.
+func (c *FailSafeCache) Delete(ctx context.Context, slug string) error {
+	c.mu.Lock()
+	c.seq++
+	c.pending[slug] = c.seq
+	c.mu.Unlock()
+
+	if err := c.breaker.Allow(); err != nil {
+		return err
+	}
+	return c.flush(ctx)
+}
+
+// Pending возвращает число slug, удаление которых ещё не дошло до Redis.
+func (c *FailSafeCache) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// flush удаляет из Redis отложенные slug. Вызывается после разрешения breaker; на первой ошибке
+// останавливается, и оставшиеся slug ждут следующей попытки.
+func (c *FailSafeCache) flush(ctx context.Context) error {
+	c.mu.Lock()
+	if len(c.pending) == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	batch := make(map[string]uint64, len(c.pending))
+	for slug, seq := range c.pending {
+		batch[slug] = seq
+	}
+	c.mu.Unlock()
+
+	for slug, seq := range batch {
+		if err := c.record(c.next.Delete(ctx, slug)); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		// Пока шло удаление, slug мог попасть в очередь снова — тогда запись остаётся
+		if c.pending[slug] == seq {
+			delete(c.pending, slug)
+		}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// Unwrap возвращает обёрнутый кэш.
+func (c *FailSafeCache) Unwrap() URLCache {
+	return c.next
+}
+
+// Breaker возвращает breaker, который следит за кэшем.
+func (c *FailSafeCache) Breaker() *breaker.Breaker {
+	return c.breaker
+}
+
+// Available сообщает, обращается ли кэш сейчас к Redis.
+func (c *FailSafeCache) Available() bool {
+	return c.breaker.State() != breaker.Open
+}
+
+// record передаёт исход обращения breaker. Промах — нормальный ответ, а отмена запроса
+// клиентом о здоровье кэша не говорит; возвращается ошибка, если это был сбой или промах.
+func (c *FailSafeCache) record(err error) error {
+	switch {
+	case err == nil, errors.Is(err, ErrCacheMiss):
+		c.breaker.Success()
+	case errors.Is(err, context.Canceled):
+		c.breaker.Release()
+	default:
+		c.breaker.Failure(err)
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestFailSafeCache_DegradesAndRecovers(t *testing.T) {
+	remote := &mocks.MockCache{}
+	log := &mocks.MockLogger{}
+	c := cache.NewFailSafeCache(remote, breaker.New("redis", breaker.Settings{
+		Threshold: 1,
+		CoolDown:  50 * time.Millisecond,
+	}, log))
+	ctx := context.Background()
+	outage := errors.New("dial tcp: connection refused")
+
+	// Переход в сбой логируется один раз, ошибка превращается в промах
+	remote.On("Get", mock.Anything, "abc").Return("", outage).Once()
+	log.On("Warn", "Circuit breaker opened", mock.Anything).Once()
+
+	_, err := c.Get(ctx, "abc")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.False(t, c.Available())
+
+	// Пока breaker открыт, Redis не опрашивается вовсе
+	for i := 0; i < 10; i++ {
+		_, err = c.Get(ctx, "abc")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+		assert.NoError(t, c.SetNX(ctx, "abc", "https://example.com/", time.Minute))
+		assert.ErrorIs(t, c.Delete(ctx, "abc"), breaker.ErrOpen)
+	}
+	remote.AssertNumberOfCalls(t, "Get", 1)
+	assert.Equal(t, 1, c.Pending())
+
+	// После паузы сначала выполняется отложенное удаление, затем пробный запрос; breaker закрывается
+	time.Sleep(60 * time.Millisecond)
+	remote.On("Delete", mock.Anything, "abc").Return(nil).Once()
+	remote.On("Get", mock.Anything, "abc").Return("https://example.com/", nil).Once()
+	log.On("Info", "Circuit breaker half-open, probing", mock.Anything).Once()
+	log.On("Info", "Circuit breaker closed", mock.Anything).Once()
+
+	url, err := c.Get(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/", url)
+	assert.True(t, c.Available())
+	assert.Zero(t, c.Pending())
+
+	remote.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestFailSafeCache_DeleteFailureIsReturnedAndRetried(t *testing.T) {
+	remote := &mocks.MockCache{}
+	c := cache.NewFailSafeCache(remote, breaker.New("redis", breaker.Settings{Threshold: 5}, &mocks.MockLogger{}))
+	ctx := context.Background()
+	outage := errors.New("i/o timeout")
+
+	remote.On("Delete", mock.Anything, "abc").Return(outage).Once()
+	assert.ErrorIs(t, c.Delete(ctx, "abc"), outage)
+	assert.Equal(t, 1, c.Pending())
+
+	// Следующее обращение к Redis сначала повторяет удаление, иначе Get вернул бы устаревший адрес
+	remote.On("Delete", mock.Anything, "abc").Return(nil).Once()
+	remote.On("Get", mock.Anything, "abc").Return("", cache.ErrCacheMiss).Once()
+
+	_, err := c.Get(ctx, "abc")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.Zero(t, c.Pending())
+	remote.AssertExpectations(t)
+}
+
+func TestFailSafeCache_MissIsNotAnOutage(t *testing.T) {
+	remote := &mocks.MockCache{}
+	log := &mocks.MockLogger{}
+	c := cache.NewFailSafeCache(remote, breaker.New("redis", breaker.Settings{Threshold: 1}, log))
+
+	remote.On("Get", mock.Anything, "abc").Return("", cache.ErrCacheMiss).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Get(context.Background(), "abc")
+		assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	}
+	assert.True(t, c.Available())
+	remote.AssertExpectations(t)
+	log.AssertNotCalled(t, "Warn", mock.Anything, mock.Anything)
+}
+
+func TestFailSafeCache_CancelledRequestIsNotAnOutage(t *testing.T) {
+	remote := &mocks.MockCache{}
+	c := cache.NewFailSafeCache(remote, breaker.New("redis", breaker.Settings{Threshold: 1}, &mocks.MockLogger{}))
+
+	remote.On("Get", mock.Anything, "abc").Return("", context.Canceled).Once()
+
+	_, err := c.Get(context.Background(), "abc")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	assert.True(t, c.Available())
+}
@@ -8,4 +8,21 @@ import (
 type URLCache interface {
 	Get(ctx context.Context, slug string) (string, error)
 	SetNX(ctx context.Context, slug, url string, ttl time.Duration) error
+	// Delete удаляет запись; отсутствие ключа ошибкой не считается.
+	Delete(ctx context.Context, slug string) error
+}
+
+// Layers возвращает цепочку слоёв кэша от внешней обёртки до самого внутреннего кэша.
+// Обёртка раскрывается, если у неё есть метод Unwrap() URLCache.
+func Layers(c URLCache) []URLCache {
+	var layers []URLCache
+	for c != nil {
+		layers = append(layers, c)
+		wrapper, ok := c.(interface{ Unwrap() URLCache })
+		if !ok {
+			break
+		}
+		c = wrapper.Unwrap()
+	}
+	return layers
 }
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TierStats — счётчики попаданий и промахов одного уровня кэша.
+type TierStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// LRUCache — кэш в памяти процесса, ограниченный по числу записей и по времени жизни.
+// При переполнении вытесняется запись, к которой дольше всего не обращались.
+type LRUCache struct {
+	mu     sync.Mutex
+	size   int
+	ttl    time.Duration
+	items  map[string]*list.Element
+	order  *list.List // в начале — самые свежие записи
+	now    func() time.Time
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type lruEntry struct {
+	slug      string
+	url       string
+	expiresAt time.Time // нулевое значение — без ограничения по времени
+}
+
+var _ URLCache = (*LRUCache)(nil)
+
+// NewLRUCache создаёт кэш на size записей. ttl ограничивает жизнь каждой записи сверху;
+// 0 — записи живут столько, сколько попросил вызывающий.
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:  max(size, 1),
+		ttl:   ttl,
+		items: make(map[string]*list.Element, size),
+		order: list.New(),
+		now:   time.Now,
+	}
+}
+
+func (c *LRUCache) Get(_ context.Context, slug string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[slug]
+	if !ok {
+		c.misses.Add(1)
+		return "", ErrCacheMiss
+	}
+	entry := el.Value.(*lruEntry)
+	if c.expired(entry) {
+		c.remove(el)
+		c.misses.Add(1)
+		return "", ErrCacheMiss
+	}
+
+	c.order.MoveToFront(el)
+	c.hits.Add(1)
+	return entry.url, nil
+}
+
+// SetNX добавляет запись, если её ещё нет (или она истекла). Срок жизни — меньший из ttl и
+// собственного TTL кэша.
+func (c *LRUCache) SetNX(_ context.Context, slug, url string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[slug]; ok {
+		if !c.expired(el.Value.(*lruEntry)) {
+			return nil
+		}
+		c.remove(el)
+	}
+
+	c.items[slug] = c.order.PushFront(&lruEntry{
+		slug:      slug,
+		url:       url,
+		expiresAt: c.expiry(ttl),
+	})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRUCache) Delete(_ context.Context, slug string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[slug]; ok {
+		c.remove(el)
+	}
+	return nil
+}
+
+// Len возвращает текущее число записей, включая ещё не вычищенные истёкшие.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats возвращает накопленные счётчики попаданий и промахов.
+func (c *LRUCache) Stats() TierStats {
+	return TierStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// expiry вычисляет момент истечения записи: берётся меньший из двух TTL, 0 означает «без ограничения».
+func (c *LRUCache) expiry(ttl time.Duration) time.Time {
+	if c.ttl > 0 && (ttl <= 0 || c.ttl < ttl) {
+		ttl = c.ttl
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(ttl)
+}
+
+func (c *LRUCache) expired(entry *lruEntry) bool {
+	return !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt)
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruEntry).slug)
+}
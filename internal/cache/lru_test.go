@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache(2, 0)
+	ctx := context.Background()
+
+	require.NoError(t, c.SetNX(ctx, "a", "https://a.example/", 0))
+	require.NoError(t, c.SetNX(ctx, "b", "https://b.example/", 0))
+
+	// Обращение к "a" делает её свежей, вытесняется "b"
+	_, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, c.SetNX(ctx, "c", "https://c.example/", 0))
+
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	url, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://a.example/", url)
+	assert.Equal(t, 2, c.Len())
+	assert.Equal(t, TierStats{Hits: 2, Misses: 1}, c.Stats())
+}
+
+func TestLRUCache_TTL(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRUCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, c.SetNX(ctx, "short", "https://short.example/", 10*time.Second))
+	require.NoError(t, c.SetNX(ctx, "long", "https://long.example/", time.Hour))
+	require.NoError(t, c.SetNX(ctx, "forever", "https://forever.example/", 0))
+
+	now = now.Add(30 * time.Second)
+	_, err := c.Get(ctx, "short")
+	assert.ErrorIs(t, err, ErrCacheMiss, "TTL записи меньше TTL кэша")
+	_, err = c.Get(ctx, "long")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = c.Get(ctx, "long")
+	assert.ErrorIs(t, err, ErrCacheMiss, "TTL кэша ограничивает TTL записи")
+	_, err = c.Get(ctx, "forever")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestLRUCache_SetNXKeepsExisting(t *testing.T) {
+	c := NewLRUCache(10, 0)
+	ctx := context.Background()
+
+	require.NoError(t, c.SetNX(ctx, "a", "https://first.example/", 0))
+	require.NoError(t, c.SetNX(ctx, "a", "https://second.example/", 0))
+
+	url, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://first.example/", url)
+
+	require.NoError(t, c.Delete(ctx, "a"))
+	_, err = c.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery — раз в сколько записей удалять из MemoryCache истёкшие ключи.
+const sweepEvery = 1024
+
+// MemoryCache — кэш в памяти процесса с TTL, без ограничения по числу записей.
+// Подходит для разработки и одиночных инстансов без Redis.
+type MemoryCache struct {
+	mu     sync.Mutex
+	items  map[string]memoryEntry
+	writes int
+	now    func() time.Time
+}
+
+type memoryEntry struct {
+	url       string
+	expiresAt time.Time // нулевое значение — без ограничения по времени
+}
+
+var _ URLCache = (*MemoryCache)(nil)
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		items: make(map[string]memoryEntry),
+		now:   time.Now,
+	}
+}
+
+func (c *MemoryCache) Get(_ context.Context, slug string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.items[slug]
+	if !ok || entry.expired(c.now()) {
+		return "", ErrCacheMiss
+	}
+	return entry.url, nil
+}
+
+func (c *MemoryCache) SetNX(_ context.Context, slug, url string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if entry, ok := c.items[slug]; ok && !entry.expired(now) {
+		return nil
+	}
+
+	entry := memoryEntry{url: url}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	c.items[slug] = entry
+
+	c.writes++
+	if c.writes%sweepEvery == 0 {
+		for k, e := range c.items {
+			if e.expired(now) {
+				delete(c.items, k)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) Delete(_ context.Context, slug string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, slug)
+	return nil
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, "abc")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, c.SetNX(ctx, "abc", "https://example.com/", time.Minute))
+	require.NoError(t, c.SetNX(ctx, "abc", "https://other.example/", time.Minute))
+	require.NoError(t, c.SetNX(ctx, "forever", "https://forever.example/", 0))
+
+	url, err := c.Get(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/", url, "SetNX не перезаписывает живую запись")
+
+	now = now.Add(time.Minute)
+	_, err = c.Get(ctx, "abc")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	_, err = c.Get(ctx, "forever")
+	assert.NoError(t, err)
+
+	// Истёкшая запись заменяется новой
+	require.NoError(t, c.SetNX(ctx, "abc", "https://other.example/", time.Minute))
+	url, _ = c.Get(ctx, "abc")
+	assert.Equal(t, "https://other.example/", url)
+
+	require.NoError(t, c.Delete(ctx, "abc"))
+	_, err = c.Get(ctx, "abc")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
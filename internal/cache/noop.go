@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// NoopCache ничего не хранит: каждое чтение — промах, и запрос всегда идёт в базу.
+type NoopCache struct{}
+
+var _ URLCache = NoopCache{}
+
+func NewNoopCache() NoopCache {
+	return NoopCache{}
+}
+
+func (NoopCache) Get(context.Context, string) (string, error) {
+	return "", ErrCacheMiss
+}
+
+func (NoopCache) SetNX(context.Context, string, string, time.Duration) error {
+	return nil
+}
+
+func (NoopCache) Delete(context.Context, string) error {
+	return nil
+}
@@ -5,8 +5,13 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Thoustick/SlugKiller/internal/tracing"
 )
 
+// redisCache работает поверх общего клиента Redis; закрывает и проверяет клиент его владелец.
 type redisCache struct {
 	Client *redis.Client
 }
@@ -16,10 +21,14 @@ func NewRedisCache(client *redis.Client) URLCache {
 }
 
 func (r *redisCache) Get(ctx context.Context, slug string) (string, error) {
+	ctx, span := startSpan(ctx, "GET")
 	val, err := r.Client.Get(ctx, slug).Result()
 	if err == redis.Nil {
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		tracing.End(span, nil)
 		return "", ErrCacheMiss
 	}
+	tracing.End(span, err)
 	if err != nil {
 		return "", err
 	}
@@ -27,5 +36,23 @@ func (r *redisCache) Get(ctx context.Context, slug string) (string, error) {
 }
 
 func (r *redisCache) SetNX(ctx context.Context, slug, url string, ttl time.Duration) error {
-	return r.Client.SetNX(ctx, slug, url, ttl).Err()
+	ctx, span := startSpan(ctx, "SETNX")
+	err := r.Client.SetNX(ctx, slug, url, ttl).Err()
+	tracing.End(span, err)
+	return err
+}
+
+func (r *redisCache) Delete(ctx context.Context, slug string) error {
+	ctx, span := startSpan(ctx, "DEL")
+	err := r.Client.Del(ctx, slug).Err()
+	tracing.End(span, err)
+	return err
+}
+
+// startSpan открывает спан команды Redis.
+func startSpan(ctx context.Context, command string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "redis "+command,
+		attribute.String("db.system", "redis"),
+		attribute.String("db.operation.name", command),
+	)
 }
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// TieredStats — счётчики обоих уровней двухуровневого кэша.
+type TieredStats struct {
+	Local  TierStats
+	Remote TierStats
+}
+
+// TieredCache ставит локальный LRU перед общим кэшем (Redis). Чтение сначала идёт в память
+// процесса, при промахе — в общий кэш, и найденная там запись поднимается в локальный уровень.
+//
+// Удаление чистит оба уровня только в текущем процессе: на других репликах устаревшая запись
+// живёт не дольше TTL локального уровня, поэтому его стоит держать коротким.
+type TieredCache struct {
+	local  *LRUCache
+	remote URLCache
+
+	remoteHits   atomic.Uint64
+	remoteMisses atomic.Uint64
+}
+
+var _ URLCache = (*TieredCache)(nil)
+
+func NewTieredCache(local *LRUCache, remote URLCache) *TieredCache {
+	return &TieredCache{local: local, remote: remote}
+}
+
+func (c *TieredCache) Get(ctx context.Context, slug string) (string, error) {
+	if url, err := c.local.Get(ctx, slug); err == nil {
+		return url, nil
+	}
+
+	url, err := c.remote.Get(ctx, slug)
+	if err != nil {
+		if errors.Is(err, ErrCacheMiss) {
+			c.remoteMisses.Add(1)
+		}
+		return "", err
+	}
+	c.remoteHits.Add(1)
+
+	// Оставшийся срок жизни в общем кэше неизвестен, поэтому запись живёт локально не дольше TTL LRU.
+	// Негативные записи не поднимаются: сканеры не должны вытеснять из LRU настоящие ссылки
+	if url != NotFound {
+		_ = c.local.SetNX(ctx, slug, url, 0)
+	}
+	return url, nil
+}
+
+// SetNX пишет в общий кэш; локальный уровень заполняется при первом чтении.
+// Так в памяти процесса оседают только действительно читаемые ссылки.
+func (c *TieredCache) SetNX(ctx context.Context, slug, url string, ttl time.Duration) error {
+	return c.remote.SetNX(ctx, slug, url, ttl)
+}
+
+func (c *TieredCache) Delete(ctx context.Context, slug string) error {
+	_ = c.local.Delete(ctx, slug)
+	return c.remote.Delete(ctx, slug)
+}
+
+// Unwrap возвращает общий уровень кэша.
+func (c *TieredCache) Unwrap() URLCache {
+	return c.remote
+}
+
+// Stats возвращает счётчики попаданий и промахов по уровням. Ошибки общего кэша
+// (кроме отсутствия ключа) промахом не считаются.
+func (c *TieredCache) Stats() TieredStats {
+	return TieredStats{
+		Local: c.local.Stats(),
+		Remote: TierStats{
+			Hits:   c.remoteHits.Load(),
+			Misses: c.remoteMisses.Load(),
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestTieredCache_PromotesRemoteHits(t *testing.T) {
+	remote := &mocks.MockCache{}
+	c := cache.NewTieredCache(cache.NewLRUCache(10, time.Minute), remote)
+	ctx := context.Background()
+
+	// Общий кэш опрашивается один раз, дальше запись отдаётся из памяти процесса
+	remote.On("Get", mock.Anything, "abc").Return("https://example.com/", nil).Once()
+
+	for i := 0; i < 3; i++ {
+		url, err := c.Get(ctx, "abc")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/", url)
+	}
+
+	remote.AssertExpectations(t)
+	assert.Equal(t, cache.TieredStats{
+		Local:  cache.TierStats{Hits: 2, Misses: 1},
+		Remote: cache.TierStats{Hits: 1},
+	}, c.Stats())
+}
+
+func TestTieredCache_Miss(t *testing.T) {
+	remote := &mocks.MockCache{}
+	c := cache.NewTieredCache(cache.NewLRUCache(10, time.Minute), remote)
+	ctx := context.Background()
+
+	remote.On("Get", mock.Anything, "missing").Return("", cache.ErrCacheMiss).Once()
+	remote.On("Get", mock.Anything, "broken").Return("", errors.New("redis down")).Once()
+
+	_, err := c.Get(ctx, "missing")
+	assert.ErrorIs(t, err, cache.ErrCacheMiss)
+	_, err = c.Get(ctx, "broken")
+	assert.EqualError(t, err, "redis down")
+
+	// Ошибка Redis промахом не считается
+	assert.Equal(t, cache.TierStats{Misses: 1}, c.Stats().Remote)
+	assert.Equal(t, cache.TierStats{Misses: 2}, c.Stats().Local)
+}
+
+func TestTieredCache_SetNXAndDelete(t *testing.T) {
+	remote := &mocks.MockCache{}
+	local := cache.NewLRUCache(10, time.Minute)
+	c := cache.NewTieredCache(local, remote)
+	ctx := context.Background()
+
+	remote.On("SetNX", mock.Anything, "abc", "https://example.com/", time.Hour).Return(nil).Once()
+	require.NoError(t, c.SetNX(ctx, "abc", "https://example.com/", time.Hour))
+	assert.Equal(t, 0, local.Len(), "локальный уровень заполняется только при чтении")
+
+	require.NoError(t, local.SetNX(ctx, "abc", "https://example.com/", 0))
+	remote.On("Delete", mock.Anything, "abc").Return(nil).Once()
+	require.NoError(t, c.Delete(ctx, "abc"))
+	assert.Equal(t, 0, local.Len())
+
+	remote.AssertExpectations(t)
+}
+
+func TestTieredCache_NegativeEntriesStayRemote(t *testing.T) {
+	remote := &mocks.MockCache{}
+	local := cache.NewLRUCache(10, time.Minute)
+	c := cache.NewTieredCache(local, remote)
+
+	remote.On("Get", mock.Anything, "nope").Return(cache.NotFound, nil).Twice()
+
+	for i := 0; i < 2; i++ {
+		val, err := c.Get(context.Background(), "nope")
+		require.NoError(t, err)
+		assert.Equal(t, cache.NotFound, val)
+	}
+	assert.Equal(t, 0, local.Len())
+	remote.AssertExpectations(t)
+}
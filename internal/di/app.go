@@ -2,59 +2,177 @@ package di
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/infrastructure/redis"
+	"github.com/Thoustick/SlugKiller/internal/analytics"
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/cache"
 	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/health"
+	"github.com/Thoustick/SlugKiller/internal/metrics"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/ratelimit"
 	"github.com/Thoustick/SlugKiller/internal/server"
 	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/slugpool"
+	"github.com/Thoustick/SlugKiller/internal/tracing"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
+// providers создают внешние зависимости приложения. NewApp и NewTestApp отличаются только ими,
+// остальная сборка общая (buildApp), поэтому тестовое приложение проверяет ту же проводку, что и боевое.
+type providers struct {
+	storage server.StorageFactory
+	redis   func(cfg *config.Config, log logger.Logger) (*redis.Client, error)
+	cache   func(cfg *config.Config, redisClient *redis.Client, log logger.Logger) (cache.URLCache, error)
+	limiter func(cfg *config.Config, redisClient *redis.Client, log logger.Logger) (ratelimit.Limiter, error)
+}
+
 func NewApp(ctx context.Context, cfg *config.Config) (*server.App, error) {
+	return buildApp(ctx, cfg, providers{
+		storage: server.ProductionStorageFactory,
+		redis:   server.ProductionRedisClient,
+		cache:   server.ProductionCacheProvider,
+		limiter: server.ProductionRateLimiter,
+	})
+}
+
+// buildApp собирает приложение из зависимостей, которые создают providers.
+func buildApp(ctx context.Context, cfg *config.Config, p providers) (*server.App, error) {
 	log := logger.InitLogger(cfg)
+	reg := metrics.NewRegistry()
 
-	// Инициализация хранилища
-	repo, err := server.ProductionStorageFactory(ctx, cfg, log)
+	if !model.IsValidRedirectType(cfg.RedirectDefaultType) {
+		err := fmt.Errorf("REDIRECT_DEFAULT_TYPE=%d: %w", cfg.RedirectDefaultType, service.ErrInvalidRedirectType)
+		log.Error("invalid redirect configuration", err, nil)
+		return nil, err
+	}
+
+	utmTemplates, err := service.LoadUTMTemplates(cfg.UTMTemplatesFile)
 	if err != nil {
-		log.Error("failed to initialize storage", err, nil)
+		log.Error("failed to load UTM templates", err, nil)
 		return nil, err
 	}
+	cfg.UTMTemplates = utmTemplates
 
-	// Инициализация кэша
-	cacheLayer, err := server.ProductionCacheProvider(cfg, log)
+	shutdownTracing, err := tracing.Setup(ctx, cfg, log)
 	if err != nil {
-		log.Error("failed to initialize cache", err, nil)
+		log.Error("failed to initialize tracing", err, nil)
 		return nil, err
 	}
 
-	slugGen := service.NewSlugGenerator(cfg.SlugLength)
+	// Всё открытое дальше закрывается, если сборка приложения не удалась
+	var res resources
+	res.add(shutdownTracing)
+	fail := func(msg string, err error) (*server.App, error) {
+		log.Error(msg, err, nil)
+		return nil, errors.Join(err, res.closeAll(ctx, cfg.ShutdownTimeout))
+	}
+
+	// Инициализация хранилища
+	repo, err := p.storage(ctx, cfg, log)
+	if err != nil {
+		return fail("failed to initialize storage", err)
+	}
+	res.addStore(repo)
+
+	if err := auth.Seed(ctx, repo, cfg.APIKeys); err != nil {
+		return fail("failed to register API keys", err)
+	}
+
+	// Один клиент Redis на кэш и лимиты
+	redisClient, err := p.redis(cfg, log)
+	if err != nil {
+		return fail("failed to connect to Redis", err)
+	}
+	res.addRedis(redisClient)
+	registerRedisMetrics(reg, redisClient)
+
+	// Инициализация кэша
+	cacheLayer, err := p.cache(cfg, redisClient, log)
+	if err != nil {
+		return fail("failed to initialize cache", err)
+	}
+	cacheLayer = metrics.InstrumentCache(cacheLayer, reg)
+
+	slugGen, err := service.NewSlugGeneratorFromConfig(cfg, repo)
+	if err != nil {
+		return fail("failed to initialize slug generator", err)
+	}
+
+	var workers []server.Worker
+	if cfg.SlugPoolSize > 0 {
+		pool, err := slugpool.New(slugGen, repo, cfg, log)
+		if err != nil {
+			return fail("failed to initialize slug pool", err)
+		}
+		slugGen = pool
+		workers = append(workers, pool)
+		metrics.RegisterSlugPool(reg, pool)
+	}
+
+	policy, policyWorkers, err := newURLPolicy(cfg, log)
+	if err != nil {
+		return fail("failed to initialize URL policy", err)
+	}
+	workers = append(workers, policyWorkers...)
+
+	urlRepo, dbBreaker := guardRepository(cfg, repo, log)
+	registerStoreMetrics(reg, repo, dbBreaker)
 
 	urlServiceInstance := service.NewURLService(
-		repo,
+		urlRepo,
 		log,
 		cacheLayer,
 		cfg,
 		slugGen,
+		policy,
 	)
+	urlServiceInstance = metrics.InstrumentURLService(urlServiceInstance, reg)
+
+	statsService := service.NewStatsService(urlRepo, guardStats(repo, dbBreaker), cfg.AdminOwners, log)
+	clickRecorder := analytics.NewRecorder(repo, cfg, log)
+	metrics.RegisterClickRecorder(reg, clickRecorder)
+
+	limiter, err := p.limiter(cfg, redisClient, log)
+	if err != nil {
+		return fail("failed to initialize rate limiter", err)
+	}
+	registerLimiterMetrics(reg, limiter)
 
-	h := handler.NewHandler(urlServiceInstance, log)
+	// Лимит проверяется до ключа: запросы с подобранными ключами не должны бесплатно нагружать базу
+	h := handler.NewHandler(urlServiceInstance, statsService, clickRecorder, log).
+		Use(rateLimitMiddleware(cfg, limiter, log)).
+		Use(authMiddleware(cfg, guardAPIKeys(repo, dbBreaker), log))
 
-	r := setupRouter(h)
+	hc := newHealth(cfg, repo, redisClient, log)
+	r := setupRouter(h, hc, tracing.Middleware(), metrics.HTTPMiddleware(reg))
+	admin := setupMetricsRoute(cfg, r, reg)
 
 	appCtx, cancel := context.WithCancel(ctx)
 
 	return &server.App{
-		Engine: r,
-		Cfg:    cfg,
-		Ctx:    appCtx,
-		Cancel: cancel,
-		Logger: log,
+		Engine:  r,
+		Cfg:     cfg,
+		Ctx:     appCtx,
+		Cancel:  cancel,
+		Logger:  log,
+		Workers: append(workers, clickRecorder),
+		Admin:   admin,
+		// Трассировка закрывается последней, чтобы выгрузить спаны, записанные при остановке
+		Closers: res.list(),
+		Health:  hc,
 	}, nil
 }
 
-func setupRouter(h handler.URLHandler) *gin.Engine {
-	r := gin.Default()  // <- Инициализация маршрутизатора Gin
-	h.RegisterRoutes(r) // <- Регистрируем маршруты
-	return r            // <- Возвращаем готовый engine
+func setupRouter(h handler.URLHandler, hc *health.Health, middleware ...gin.HandlerFunc) *gin.Engine {
+	r := gin.Default()   // <- Инициализация маршрутизатора Gin
+	hc.Register(r)       // <- Проверки здоровья — до middleware, чтобы не засорять трассы и метрики
+	r.Use(middleware...) // <- Общие middleware (трассировка, метрики)
+	h.RegisterRoutes(r)  // <- Регистрируем маршруты
+	return r             // <- Возвращаем готовый engine
 }
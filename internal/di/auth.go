@@ -0,0 +1,20 @@
+package di
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// authMiddleware требует API-ключ для управления ссылками и, если анонимное сокращение
+// выключено, для POST /shorten. Редирект доступен всем.
+func authMiddleware(cfg *config.Config, keys repository.APIKeyStore, log logger.Logger) handler.Middleware {
+	return handler.Middleware{
+		Shorten: []gin.HandlerFunc{auth.Middleware(keys, !cfg.AllowAnonymousShorten, log)},
+		Links:   []gin.HandlerFunc{auth.Middleware(keys, true, log)},
+	}
+}
@@ -0,0 +1,38 @@
+package di
+
+import (
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/storage"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// guardRepository ставит circuit breaker перед хранилищем ссылок, если он включён в конфигурации.
+// Возвращает и сам breaker (nil, если выключен), чтобы зарегистрировать его метрики.
+func guardRepository(cfg *config.Config, repo repository.URLRepository, log logger.Logger) (repository.URLRepository, *breaker.Breaker) {
+	if cfg.DBBreakerThreshold <= 0 {
+		return repo, nil
+	}
+	b := breaker.New("storage", breaker.Settings{
+		Threshold: cfg.DBBreakerThreshold,
+		CoolDown:  cfg.DBBreakerCoolDown,
+	}, log)
+	return storage.WithBreaker(repo, b), b
+}
+
+// guardStats ставит перед чтением статистики breaker базы; без breaker статистика читается напрямую.
+func guardStats(stats repository.StatsReader, b *breaker.Breaker) repository.StatsReader {
+	if b == nil {
+		return stats
+	}
+	return storage.WithBreakerStats(stats, b)
+}
+
+// guardAPIKeys ставит перед хранилищем API-ключей breaker базы; без breaker ключи читаются напрямую.
+func guardAPIKeys(keys repository.APIKeyStore, b *breaker.Breaker) repository.APIKeyStore {
+	if b == nil {
+		return keys
+	}
+	return storage.WithBreakerKeys(keys, b)
+}
@@ -0,0 +1,84 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/infrastructure/redis"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// defaultCloseTimeout — сколько ждать закрытия ресурсов при неудачном запуске, если SHUTDOWN_TIMEOUT_SECONDS не задан.
+const defaultCloseTimeout = 10 * time.Second
+
+// resources копит закрытие ресурсов по мере их открытия, чтобы при ошибке сборки приложения
+// закрыть уже открытое, а при успешной — передать список в App.Closers. Закрываются ресурсы
+// в обратном порядке: сначала соединения, последней — трассировка, открытая первой.
+type resources struct {
+	closers []func(context.Context) error
+}
+
+func (r *resources) add(closeFn func(context.Context) error) {
+	r.closers = append(r.closers, closeFn)
+}
+
+// addStore добавляет хранилище, если его нужно закрывать; хранилища в памяти пропускаются.
+func (r *resources) addStore(repo repository.Store) {
+	if db, ok := repo.(io.Closer); ok {
+		r.add(closeWithContext("postgres", db))
+	}
+}
+
+// addRedis добавляет общий клиент Redis; nil — Redis не используется.
+func (r *resources) addRedis(client *redis.Client) {
+	if client != nil {
+		r.add(closeWithContext("redis", client))
+	}
+}
+
+// list возвращает закрытие в порядке остановки.
+func (r *resources) list() []func(context.Context) error {
+	list := make([]func(context.Context) error, len(r.closers))
+	for i, closeFn := range r.closers {
+		list[len(r.closers)-1-i] = closeFn
+	}
+	return list
+}
+
+// closeAll закрывает всё открытое после неудачной сборки приложения; сбой одного закрытия
+// не мешает остальным. Отмена ctx (например, сигналом) закрытие не прерывает.
+func (r *resources) closeAll(ctx context.Context, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultCloseTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, closeFn := range r.list() {
+		errs = append(errs, closeFn(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// closeWithContext не даёт зависшему закрытию (например, пулу с неотпущенным соединением)
+// задержать остановку дольше таймаута.
+func closeWithContext(name string, c io.Closer) func(context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan error, 1)
+		go func() { done <- c.Close() }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("close %s: %w", name, err)
+			}
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("close %s: %w", name, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package di
+
+import (
+	"context"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/infrastructure/redis"
+	"github.com/Thoustick/SlugKiller/internal/health"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// newHealth собирает проверки /readyz. База критична: без неё не работают ни создание, ни редирект.
+// Redis — нет: кэш при его сбое превращается в промахи, а лимиты пропускают запросы.
+// redisClient — nil, если Redis не используется.
+func newHealth(cfg *config.Config, repo repository.Store, redisClient *redis.Client, log logger.Logger) *health.Health {
+	h := health.New(cfg.HealthCheckTimeout, log)
+	if db, ok := repo.(pinger); ok {
+		h.AddCritical("postgres", db.Ping)
+	}
+	if redisClient != nil {
+		h.AddOptional("redis", redisClient.Ping)
+	}
+	return h
+}
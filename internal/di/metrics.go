@@ -0,0 +1,53 @@
+package di
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/infrastructure/redis"
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/metrics"
+	"github.com/Thoustick/SlugKiller/internal/ratelimit"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// registerStoreMetrics регистрирует показатели хранилища: пул соединений PostgreSQL и breaker базы.
+func registerStoreMetrics(reg prometheus.Registerer, repo repository.Store, dbBreaker *breaker.Breaker) {
+	if pool, ok := repo.(metrics.PgxPoolStatter); ok {
+		metrics.RegisterPgxPool(reg, pool)
+	}
+	if dbBreaker != nil {
+		metrics.RegisterBreaker(reg, dbBreaker)
+	}
+}
+
+// registerRedisMetrics регистрирует пул соединений общего клиента Redis, если он используется.
+func registerRedisMetrics(reg prometheus.Registerer, redisClient *redis.Client) {
+	if redisClient != nil {
+		metrics.RegisterRedisPool(reg, "shared", redisClient.PoolStats)
+	}
+}
+
+// registerLimiterMetrics регистрирует breaker лимитера, если лимиты хранятся в Redis.
+func registerLimiterMetrics(reg prometheus.Registerer, limiter ratelimit.Limiter) {
+	if guarded, ok := limiter.(interface{ Breaker() *breaker.Breaker }); ok {
+		metrics.RegisterBreaker(reg, guarded.Breaker())
+	}
+}
+
+// setupMetricsRoute отдаёт /metrics на основном роутере или, если задан METRICS_ADDR,
+// возвращает обработчик для отдельного служебного адреса, чтобы метрики не были доступны снаружи.
+func setupMetricsRoute(cfg *config.Config, r *gin.Engine, reg *prometheus.Registry) http.Handler {
+	h := metrics.Handler(reg)
+	if cfg.MetricsAddr == "" {
+		r.GET("/metrics", gin.WrapH(h))
+		return nil
+	}
+
+	admin := http.NewServeMux()
+	admin.Handle("/metrics", h)
+	return admin
+}
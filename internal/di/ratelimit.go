@@ -0,0 +1,25 @@
+package di
+
+import (
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/ratelimit"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitMiddleware собирает ограничения частоты для маршрутов; незаданные лимиты пропускаются.
+func rateLimitMiddleware(cfg *config.Config, limiter ratelimit.Limiter, log logger.Logger) handler.Middleware {
+	var m handler.Middleware
+
+	shorten := ratelimit.Limit{Requests: cfg.ShortenRateLimit, Window: cfg.ShortenRateWindow}
+	if shorten.Enabled() {
+		m.Shorten = []gin.HandlerFunc{ratelimit.Middleware(limiter, "shorten", shorten, ratelimit.ByAPIKeyOrClientIP, log)}
+	}
+
+	resolve := ratelimit.Limit{Requests: cfg.ResolveRateLimit, Window: cfg.ResolveRateWindow}
+	if resolve.Enabled() {
+		m.Resolve = []gin.HandlerFunc{ratelimit.Middleware(limiter, "resolve", resolve, ratelimit.ByAPIKeyOrClientIP, log)}
+	}
+	return m
+}
@@ -4,42 +4,31 @@ import (
 	"context"
 
 	"github.com/Thoustick/SlugKiller/config"
-	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/infrastructure/redis"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/ratelimit"
 	"github.com/Thoustick/SlugKiller/internal/server"
-	"github.com/Thoustick/SlugKiller/internal/service"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
-	"github.com/gin-gonic/gin"
 )
 
+// NewTestApp собирает приложение так же, как NewApp, но с переданными хранилищем и кэшем.
+// Redis не используется: лимиты считаются в памяти.
 func NewTestApp(
 	ctx context.Context,
 	cfg *config.Config,
 	storageFactory server.StorageFactory,
 	cacheProvider server.CacheProvider,
 ) (*server.App, error) {
-	log := logger.InitLogger(cfg)
-
-	repo, err := storageFactory(ctx, cfg, log)
-	if err != nil {
-		return nil, err
-	}
-
-	cacheLayer, err := cacheProvider(cfg, log)
-	if err != nil {
-		return nil, err
-	}
-
-	slugGen := service.NewSlugGenerator(cfg.SlugLength)
-	urlService := service.NewURLService(repo, log, cacheLayer, cfg, slugGen)
-
-	h := handler.NewHandler(urlService, log)
-	engine := gin.Default()
-	h.RegisterRoutes(engine)
-
-	return &server.App{
-		Engine: engine,
-		Cfg:    cfg,
-		Ctx:    ctx,
-		Logger: log,
-	}, nil
+	return buildApp(ctx, cfg, providers{
+		storage: storageFactory,
+		redis: func(*config.Config, logger.Logger) (*redis.Client, error) {
+			return nil, nil
+		},
+		cache: func(cfg *config.Config, _ *redis.Client, log logger.Logger) (cache.URLCache, error) {
+			return cacheProvider(cfg, log)
+		},
+		limiter: func(*config.Config, *redis.Client, logger.Logger) (ratelimit.Limiter, error) {
+			return ratelimit.NewMemoryLimiter(), nil
+		},
+	})
 }
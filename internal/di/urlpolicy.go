@@ -0,0 +1,22 @@
+package di
+
+import (
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/server"
+	"github.com/Thoustick/SlugKiller/internal/urlpolicy"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// newURLPolicy собирает политику адресов назначения. Если задан файл со списком
+// запрещённых доменов, возвращает также воркер, который перечитывает его при изменении.
+func newURLPolicy(cfg *config.Config, log logger.Logger) (*urlpolicy.Policy, []server.Worker, error) {
+	if cfg.URLBlocklistFile == "" {
+		return urlpolicy.New(cfg, nil), nil, nil
+	}
+
+	blocklist, err := urlpolicy.NewBlocklist(cfg.URLBlocklistFile, cfg.URLBlocklistReload, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return urlpolicy.New(cfg, blocklist), []server.Worker{blocklist}, nil
+}
@@ -1,9 +1,275 @@
 package handler
 
+import (
+	"errors"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
+
 type ShortenRequest struct {
-	URL string `json:"url" binding:"required,url"`
+	URL       string     `json:"url" binding:"required,url"`
+	ExpiresAt *time.Time `json:"expires_at"` // абсолютный момент истечения (RFC 3339)
+	TTL       string     `json:"ttl"`        // время жизни в формате Go duration, например "72h"
+	Alias     string     `json:"alias"`      // желаемый slug, например "spring-sale"
+
+	RedirectType int `json:"redirect_type"` // 301, 302, 307 или 308; не задан — тип по умолчанию
+
+	QueryPassthrough string `json:"query_passthrough"` // "keep", "override" или "append"; не задан — параметры не передаются
+	PathPassthrough  bool   `json:"path_passthrough"`  // дописывать хвост пути после slug
+
+	UTM         *UTMParams `json:"utm"`          // UTM-метки, дописываемые при переходе
+	UTMTemplate string     `json:"utm_template"` // имя шаблона из UTM_TEMPLATES_FILE
+
+	Rules []LinkRuleParams `json:"rules" binding:"omitempty,dive"` // правила перехода по устройству, проверяются по порядку
+
+	Targets       []LinkTargetParams `json:"targets" binding:"omitempty,dive"` // варианты для A/B-разбиения
+	StickyTargets bool               `json:"sticky_targets"`                   // закреплять вариант за посетителем cookie
+}
+
+// UTMParams — UTM-метки ссылки; пустые поля не дописываются.
+type UTMParams struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// toModel переводит метки запроса в модель; nil остаётся nil.
+func (p *UTMParams) toModel() *model.UTM {
+	if p == nil {
+		return nil
+	}
+	utm := model.UTM(*p)
+	return &utm
+}
+
+func newUTMParams(utm *model.UTM) *UTMParams {
+	if utm == nil {
+		return nil
+	}
+	p := UTMParams(*utm)
+	return &p
+}
+
+// LinkRuleParams — правило перехода: посетитель, подходящий под все заданные условия, уходит на url.
+type LinkRuleParams struct {
+	OS     string `json:"os,omitempty"`     // ios, android, windows, macos, chromeos, linux или other
+	Device string `json:"device,omitempty"` // mobile, tablet или desktop
+	Bot    *bool  `json:"bot,omitempty"`    // true — только роботы, false — только люди
+	URL    string `json:"url" binding:"required,url"`
+}
+
+func rulesToModel(params []LinkRuleParams) []model.LinkRule {
+	if params == nil {
+		return nil
+	}
+	rules := make([]model.LinkRule, 0, len(params))
+	for _, p := range params {
+		rules = append(rules, model.LinkRule(p))
+	}
+	return rules
+}
+
+func newLinkRuleParams(rules []model.LinkRule) []LinkRuleParams {
+	if len(rules) == 0 {
+		return nil
+	}
+	params := make([]LinkRuleParams, 0, len(rules))
+	for _, r := range rules {
+		params = append(params, LinkRuleParams(r))
+	}
+	return params
+}
+
+// LinkTargetParams — вариант адреса назначения в A/B-разбиении.
+type LinkTargetParams struct {
+	Variant string `json:"variant" binding:"required"`
+	URL     string `json:"url" binding:"required,url"`
+	Weight  int    `json:"weight" binding:"required"`
+}
+
+func targetsToModel(params []LinkTargetParams) []model.LinkTarget {
+	if params == nil {
+		return nil
+	}
+	targets := make([]model.LinkTarget, 0, len(params))
+	for _, p := range params {
+		targets = append(targets, model.LinkTarget(p))
+	}
+	return targets
+}
+
+func newLinkTargetParams(targets []model.LinkTarget) []LinkTargetParams {
+	if len(targets) == 0 {
+		return nil
+	}
+	params := make([]LinkTargetParams, 0, len(targets))
+	for _, t := range targets {
+		params = append(params, LinkTargetParams(t))
+	}
+	return params
 }
 
 type ShortenResponse struct {
-	Slug string `json:"slug"`
+	Slug      string     `json:"slug"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// LinkResponse — метаданные ссылки в API управления.
+type LinkResponse struct {
+	Slug      string     `json:"slug"`
+	URL       string     `json:"url"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	RedirectType int `json:"redirect_type,omitempty"` // не задан — тип по умолчанию из конфигурации
+
+	QueryPassthrough string     `json:"query_passthrough,omitempty"`
+	PathPassthrough  bool       `json:"path_passthrough,omitempty"`
+	UTM              *UTMParams `json:"utm,omitempty"`
+	UTMTemplate      string     `json:"utm_template,omitempty"`
+
+	Rules []LinkRuleParams `json:"rules,omitempty"`
+
+	Targets       []LinkTargetParams `json:"targets,omitempty"`
+	StickyTargets bool               `json:"sticky_targets,omitempty"`
+}
+
+// UpdateLinkRequest — частичное обновление ссылки: отсутствующие поля не меняются.
+type UpdateLinkRequest struct {
+	URL    *string `json:"url" binding:"omitempty,url"`
+	Status *string `json:"status" binding:"omitempty,oneof=active disabled"`
+
+	RedirectType *int `json:"redirect_type"` // 0 — вернуться к типу по умолчанию
+
+	QueryPassthrough *string `json:"query_passthrough"` // пустая строка выключает передачу параметров
+	PathPassthrough  *bool   `json:"path_passthrough"`
+
+	UTM         *UTMParams `json:"utm"`          // заменяет метки целиком; {} удаляет их
+	UTMTemplate *string    `json:"utm_template"` // пустая строка отвязывает шаблон
+
+	Rules *[]LinkRuleParams `json:"rules" binding:"omitempty,dive"` // заменяет правила целиком; [] удаляет их
+
+	Targets       *[]LinkTargetParams `json:"targets" binding:"omitempty,dive"` // заменяет варианты целиком; [] выключает разбиение
+	StickyTargets *bool               `json:"sticky_targets"`
+}
+
+// rules возвращает новые правила перехода; nil — правила не меняются.
+func (r UpdateLinkRequest) rules() *[]model.LinkRule {
+	if r.Rules == nil {
+		return nil
+	}
+	rules := rulesToModel(*r.Rules)
+	return &rules
+}
+
+// targets возвращает новые варианты A/B-разбиения; nil — варианты не меняются.
+func (r UpdateLinkRequest) targets() *[]model.LinkTarget {
+	if r.Targets == nil {
+		return nil
+	}
+	targets := targetsToModel(*r.Targets)
+	return &targets
+}
+
+func newLinkResponse(link *model.Link) LinkResponse {
+	return LinkResponse{
+		Slug:      link.Slug,
+		URL:       link.URL,
+		Status:    link.Status,
+		CreatedAt: link.CreatedAt,
+		ExpiresAt: link.ExpiresAt,
+
+		RedirectType: link.RedirectType,
+
+		QueryPassthrough: link.QueryPassthrough,
+		PathPassthrough:  link.PathPassthrough,
+		UTM:              newUTMParams(link.UTM),
+		UTMTemplate:      link.UTMTemplate,
+
+		Rules: newLinkRuleParams(link.Rules),
+
+		Targets:       newLinkTargetParams(link.Targets),
+		StickyTargets: link.StickyTargets,
+	}
+}
+
+// LinkStatsResponse — статистика переходов по ссылке.
+type LinkStatsResponse struct {
+	Slug           string           `json:"slug"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	Interval       string           `json:"interval"`
+	TotalClicks    int64            `json:"total_clicks"`
+	UniqueVisitors int64            `json:"unique_visitors"`
+	Series         []StatsBucketDTO `json:"series"`
+	TopReferrers   []StatsCountDTO  `json:"top_referrers"`
+	TopUserAgents  []StatsCountDTO  `json:"top_user_agents"`
+	Variants       []StatsCountDTO  `json:"variants"`
+}
+
+type StatsBucketDTO struct {
+	Start          time.Time `json:"start"`
+	Clicks         int64     `json:"clicks"`
+	UniqueVisitors int64     `json:"unique_visitors"`
+}
+
+type StatsCountDTO struct {
+	Value  string `json:"value"`
+	Clicks int64  `json:"clicks"`
+}
+
+func newLinkStatsResponse(slug string, q model.StatsQuery, stats *model.LinkStats) LinkStatsResponse {
+	resp := LinkStatsResponse{
+		Slug:           slug,
+		From:           q.From,
+		To:             q.To,
+		Interval:       q.Interval,
+		TotalClicks:    stats.TotalClicks,
+		UniqueVisitors: stats.UniqueVisitors,
+		Series:         make([]StatsBucketDTO, 0, len(stats.Series)),
+		TopReferrers:   newStatsCounts(stats.TopReferrers),
+		TopUserAgents:  newStatsCounts(stats.TopUserAgents),
+		Variants:       newStatsCounts(stats.Variants),
+	}
+	for _, b := range stats.Series {
+		resp.Series = append(resp.Series, StatsBucketDTO(b))
+	}
+	return resp
+}
+
+func newStatsCounts(counts []model.StatsCount) []StatsCountDTO {
+	dto := make([]StatsCountDTO, 0, len(counts))
+	for _, c := range counts {
+		dto = append(dto, StatsCountDTO(c))
+	}
+	return dto
+}
+
+var errConflictingExpiry = errors.New("only one of expires_at and ttl may be set")
+
+// expiresAt вычисляет момент истечения ссылки из expires_at или ttl.
+// Возвращает nil, если ссылка бессрочная.
+func (r ShortenRequest) expiresAt(now time.Time) (*time.Time, error) {
+	switch {
+	case r.ExpiresAt != nil && r.TTL != "":
+		return nil, errConflictingExpiry
+	case r.ExpiresAt != nil:
+		return r.ExpiresAt, nil
+	case r.TTL != "":
+		ttl, err := time.ParseDuration(r.TTL)
+		if err != nil {
+			return nil, err
+		}
+		if ttl <= 0 {
+			return nil, errors.New("ttl must be positive")
+		}
+		t := now.Add(ttl)
+		return &t, nil
+	default:
+		return nil, nil
+	}
 }
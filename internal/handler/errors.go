@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// unavailableBody — ответ на запрос, пока хранилище недоступно (открыт circuit breaker).
+var unavailableBody = gin.H{"error": "Service temporarily unavailable"}
+
+// shortenErrorResponse сопоставляет ошибку сервиса при сокращении с HTTP-статусом и телом ответа.
+func shortenErrorResponse(err error) (int, gin.H) {
+	if status, body, ok := policyErrorResponse(err); ok {
+		return status, body
+	}
+
+	switch {
+	case errors.Is(err, service.ErrInvalidExpiry):
+		return http.StatusBadRequest, gin.H{"error": "Invalid expiration: " + err.Error()}
+	case service.IsRejected(err):
+		return http.StatusBadRequest, gin.H{"error": err.Error()}
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return http.StatusConflict, gin.H{"error": "Alias already taken"}
+	case errors.Is(err, repository.ErrUnavailable):
+		return http.StatusServiceUnavailable, unavailableBody
+	default:
+		return http.StatusInternalServerError, gin.H{"error": "Failed to shorten URL"}
+	}
+}
+
+// policyErrorResponse отвечает 422 с кодом причины, если адрес отклонён политикой.
+func policyErrorResponse(err error) (int, gin.H, bool) {
+	var violation *service.URLPolicyError
+	if !errors.As(err, &violation) {
+		return 0, nil, false
+	}
+	return http.StatusUnprocessableEntity, gin.H{
+		"error":  violation.Detail,
+		"reason": violation.Reason,
+	}, true
+}
@@ -1,32 +1,70 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Thoustick/SlugKiller/internal/analytics"
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tracing"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Handler struct {
-	service service.URLService
-	logger  logger.Logger
+	service    service.URLService
+	stats      service.StatsService
+	clicks     analytics.ClickRecorder
+	logger     logger.Logger
+	middleware Middleware
 }
 
-func NewHandler(s service.URLService, l logger.Logger) *Handler {
+// Middleware — дополнительные обработчики отдельных маршрутов (аутентификация, ограничение частоты).
+type Middleware struct {
+	Shorten []gin.HandlerFunc
+	Resolve []gin.HandlerFunc
+	Links   []gin.HandlerFunc // API управления ссылками и статистики
+}
+
+// NewHandler создаёт обработчики HTTP-запросов. clicks может быть nil — тогда переходы не учитываются.
+func NewHandler(
+	s service.URLService,
+	stats service.StatsService,
+	clicks analytics.ClickRecorder,
+	l logger.Logger,
+) *Handler {
 	return &Handler{
 		service: s,
+		stats:   stats,
+		clicks:  clicks,
 		logger:  l,
 	}
 }
 
+// Use подключает middleware к маршрутам; вызывается до RegisterRoutes.
+// Повторные вызовы добавляют обработчики после уже подключённых.
+func (h *Handler) Use(m Middleware) *Handler {
+	h.middleware.Shorten = append(h.middleware.Shorten, m.Shorten...)
+	h.middleware.Resolve = append(h.middleware.Resolve, m.Resolve...)
+	h.middleware.Links = append(h.middleware.Links, m.Links...)
+	return h
+}
+
 func (h *Handler) ShortenURL(c *gin.Context) {
 	h.logger.Info("Handling shorten request", map[string]interface{}{
 		"method": c.Request.Method,
 		"path":   c.Request.URL.Path,
 		"ip":     c.ClientIP(),
 	})
-	ctx := c.Request.Context()
+	ctx, span := tracing.Start(c.Request.Context(), "Handler.ShortenURL")
+	defer span.End()
+
 	var req ShortenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("Invalid shorten request", map[string]interface{}{
@@ -36,12 +74,45 @@ func (h *Handler) ShortenURL(c *gin.Context) {
 		return
 	}
 
-	slug, err := h.service.Shorten(ctx, req.URL)
+	expiresAt, err := req.expiresAt(time.Now())
 	if err != nil {
-		h.logger.Error("Failed to shorten URL", err, map[string]interface{}{
-			"url": req.URL,
+		h.logger.Warn("Invalid link expiration", map[string]interface{}{
+			"error": err.Error(),
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to shorten URL"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expiration: " + err.Error()})
+		return
+	}
+
+	slug, err := h.service.Shorten(ctx, req.URL, service.ShortenOptions{
+		ExpiresAt: expiresAt,
+		Alias:     req.Alias,
+		OwnerID:   auth.OwnerID(c),
+
+		RedirectType: req.RedirectType,
+
+		QueryPassthrough: req.QueryPassthrough,
+		PathPassthrough:  req.PathPassthrough,
+		UTM:              req.UTM.toModel(),
+		UTMTemplate:      req.UTMTemplate,
+
+		Rules: rulesToModel(req.Rules),
+
+		Targets:       targetsToModel(req.Targets),
+		StickyTargets: req.StickyTargets,
+	})
+	if err != nil {
+		status, body := shortenErrorResponse(err)
+		if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to shorten URL", err, map[string]interface{}{
+				"url": req.URL,
+			})
+		} else {
+			h.logger.Warn("Shorten request rejected", map[string]interface{}{
+				"url":   req.URL,
+				"error": err.Error(),
+			})
+		}
+		c.JSON(status, body)
 		return
 	}
 
@@ -50,7 +121,7 @@ func (h *Handler) ShortenURL(c *gin.Context) {
 		"slug": slug,
 	})
 
-	c.JSON(http.StatusOK, ShortenResponse{Slug: slug})
+	c.JSON(http.StatusOK, ShortenResponse{Slug: slug, ExpiresAt: expiresAt})
 }
 
 func (h *Handler) ResolveURL(c *gin.Context) {
@@ -62,9 +133,32 @@ func (h *Handler) ResolveURL(c *gin.Context) {
 		"method": c.Request.Method,
 	})
 
-	ctx := c.Request.Context()
-	originalURL, err := h.service.Resolve(ctx, slug)
-	if err != nil {
+	ctx, span := tracing.Start(c.Request.Context(), "Handler.ResolveURL", attribute.String("link.slug", slug))
+	defer span.End()
+
+	redirect, err := h.service.Resolve(ctx, slug, service.Visitor{
+		UserAgent: c.Request.UserAgent(),
+		Variant:   variantFromCookie(c),
+	})
+	switch {
+	case errors.Is(err, service.ErrLinkExpired):
+		h.logger.Warn("Slug expired", map[string]interface{}{
+			"slug": slug,
+		})
+		c.JSON(http.StatusGone, gin.H{"error": "Link expired"})
+		return
+	case errors.Is(err, service.ErrLinkDisabled):
+		h.logger.Warn("Slug disabled", map[string]interface{}{
+			"slug": slug,
+		})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link disabled"})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		redirect = nil
+	case errors.Is(err, repository.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, unavailableBody)
+		return
+	case err != nil:
 		h.logger.Error("Failed to resolve URL", err, map[string]interface{}{
 			"slug": slug,
 		})
@@ -72,7 +166,13 @@ func (h *Handler) ResolveURL(c *gin.Context) {
 		return
 	}
 
-	if originalURL == "" {
+	// Хвост пути без разрешения ссылки ведёт себя как раньше, когда такого маршрута не было
+	suffix := c.Param("path")
+	if redirect != nil && !redirect.PathPassthrough && strings.Trim(suffix, "/") != "" {
+		redirect = nil
+	}
+
+	if redirect == nil || redirect.URL == "" {
 		h.logger.Warn("Slug not found", map[string]interface{}{
 			"slug": slug,
 		})
@@ -80,10 +180,45 @@ func (h *Handler) ResolveURL(c *gin.Context) {
 		return
 	}
 
+	location := redirect.Location(suffix, c.Request.URL.Query())
 	h.logger.Info("Redirecting to original URL", map[string]interface{}{
-		"slug": slug,
-		"url":  originalURL,
+		"slug":   slug,
+		"url":    location,
+		"status": redirect.Status,
 	})
 
-	c.Redirect(http.StatusMovedPermanently, originalURL)
+	h.recordClick(c, slug, redirect.Variant)
+	c.Header("Cache-Control", redirectCacheControl(redirect.MaxAge))
+	if redirect.VaryUserAgent {
+		c.Header("Vary", "User-Agent")
+	}
+	if redirect.Variant != "" && redirect.VariantMaxAge > 0 {
+		setVariantCookie(c, slug, redirect.Variant, redirect.VariantMaxAge)
+	}
+	c.Redirect(redirect.Status, location)
+}
+
+// redirectCacheControl возвращает заголовок Cache-Control для перенаправления: постоянное
+// браузер может запомнить на maxAge, временное не кэшируется, чтобы смена адреса
+// назначения или отключение ссылки сразу доходили до вернувшихся пользователей.
+func redirectCacheControl(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "no-store"
+	}
+	return "public, max-age=" + strconv.Itoa(int(maxAge/time.Second))
+}
+
+// recordClick передаёт событие перехода в аналитику; запись в хранилище происходит асинхронно.
+func (h *Handler) recordClick(c *gin.Context, slug, variant string) {
+	if h.clicks == nil {
+		return
+	}
+	h.clicks.Record(analytics.ClickEvent{
+		Slug:      slug,
+		At:        time.Now(),
+		Referrer:  c.Request.Referer(),
+		UserAgent: c.Request.UserAgent(),
+		ClientIP:  c.ClientIP(),
+		Variant:   variant,
+	})
 }
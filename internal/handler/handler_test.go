@@ -7,12 +7,18 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/Thoustick/SlugKiller/internal/analytics"
 	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
 	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
 )
 
@@ -25,12 +31,12 @@ func TestShortenURL_Success(t *testing.T) {
 	// 1. Мокаем зависимости
 	svc := new(mocks.MockURLService)
 	log := new(mocks.MockLogger)
-	h := handler.NewHandler(svc, log)
+	h := handler.NewHandler(svc, nil, nil, log)
 
 	// 2. Настраиваем мок
 	testURL := "https://example.com"
 	testSlug := "abc123"
-	svc.On("Shorten", mock.Anything, testURL).Return(testSlug, nil)
+	svc.On("Shorten", mock.Anything, testURL, service.ShortenOptions{}).Return(testSlug, nil)
 
 	// Логгер можем не проверять досконально
 	log.On("Info", mock.Anything, mock.Anything).Maybe()
@@ -60,7 +66,7 @@ func TestShortenURL_Success(t *testing.T) {
 func TestShortenURL_InvalidJSON(t *testing.T) {
 	svc := new(mocks.MockURLService)
 	log := new(mocks.MockLogger)
-	h := handler.NewHandler(svc, log)
+	h := handler.NewHandler(svc, nil, nil, log)
 
 	log.On("Info", "Handling shorten request", mock.Anything).Once()
 	log.On("Warn", "Invalid shorten request", mock.Anything).Once()
@@ -77,17 +83,17 @@ func TestShortenURL_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 	assert.Contains(t, w.Body.String(), "Invalid request")
 
-	svc.AssertNotCalled(t, "Shorten", mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "Shorten", mock.Anything, mock.Anything, mock.Anything)
 	log.AssertExpectations(t)
 }
 
 func TestShortenURL_ServiceError(t *testing.T) {
 	svc := new(mocks.MockURLService)
 	log := new(mocks.MockLogger)
-	h := handler.NewHandler(svc, log)
+	h := handler.NewHandler(svc, nil, nil, log)
 
 	url := "https://fail.com"
-	svc.On("Shorten", mock.Anything, url).Return("", errors.New("db down")).Once()
+	svc.On("Shorten", mock.Anything, url, service.ShortenOptions{}).Return("", errors.New("db down")).Once()
 
 	log.On("Info", "Handling shorten request", mock.Anything).Once()
 	log.On("Error", "Failed to shorten URL", mock.Anything, mock.Anything).Once()
@@ -109,16 +115,21 @@ func TestShortenURL_ServiceError(t *testing.T) {
 	log.AssertExpectations(t)
 }
 
+// permanentRedirect — ответ сервиса для ссылки с постоянным перенаправлением.
+func permanentRedirect(url string) *service.Redirect {
+	return &service.Redirect{URL: url, Status: http.StatusMovedPermanently, MaxAge: time.Hour}
+}
+
 func TestResolveURL_Success(t *testing.T) {
 	svc := new(mocks.MockURLService)
 	log := new(mocks.MockLogger)
-	h := handler.NewHandler(svc, log)
+	h := handler.NewHandler(svc, nil, nil, log)
 
 	slug := "abc123"
 	originalURL := "https://go.dev"
 
 	// Настраиваем мок
-	svc.On("Resolve", mock.Anything, slug).Return(originalURL, nil).Once()
+	svc.On("Resolve", mock.Anything, slug, mock.Anything).Return(permanentRedirect(originalURL), nil).Once()
 	log.On("Info", "Handling resolve request", mock.Anything).Maybe()
 	log.On("Info", "Redirecting to original URL", mock.Anything).Once()
 
@@ -134,6 +145,7 @@ func TestResolveURL_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusMovedPermanently, w.Code)
 	assert.Equal(t, originalURL, w.Header().Get("Location"))
+	assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
 
 	svc.AssertExpectations(t)
 	log.AssertExpectations(t)
@@ -142,12 +154,12 @@ func TestResolveURL_Success(t *testing.T) {
 func TestResolveURL_ServiceError(t *testing.T) {
 	svc := new(mocks.MockURLService)
 	log := new(mocks.MockLogger)
-	h := handler.NewHandler(svc, log)
+	h := handler.NewHandler(svc, nil, nil, log)
 
 	slug := "fail"
 	testErr := errors.New("db error")
 
-	svc.On("Resolve", mock.Anything, slug).Return("", testErr).Once()
+	svc.On("Resolve", mock.Anything, slug, mock.Anything).Return(nil, testErr).Once()
 
 	log.On("Info", "Handling resolve request", mock.Anything).Once()
 	log.On("Error", "Failed to resolve URL", testErr, mock.Anything).Once()
@@ -171,11 +183,11 @@ func TestResolveURL_ServiceError(t *testing.T) {
 func TestResolveURL_NotFound(t *testing.T) {
 	svc := new(mocks.MockURLService)
 	log := new(mocks.MockLogger)
-	h := handler.NewHandler(svc, log)
+	h := handler.NewHandler(svc, nil, nil, log)
 
 	slug := "notfound"
 	// Метод Resolve вернул пустую строку (типа slug не найден)
-	svc.On("Resolve", mock.Anything, slug).Return("", nil).Once()
+	svc.On("Resolve", mock.Anything, slug, mock.Anything).Return(nil, nil).Once()
 
 	log.On("Info", "Handling resolve request", mock.Anything).Once()
 	log.On("Warn", "Slug not found", mock.Anything).Once()
@@ -195,3 +207,481 @@ func TestResolveURL_NotFound(t *testing.T) {
 	svc.AssertExpectations(t)
 	log.AssertExpectations(t)
 }
+
+func TestShortenURL_WithTTL(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	testURL := "https://example.com/promo"
+	before := time.Now()
+	svc.On("Shorten", mock.Anything, testURL, mock.MatchedBy(func(opts service.ShortenOptions) bool {
+		return opts.ExpiresAt != nil &&
+			!opts.ExpiresAt.Before(before.Add(time.Hour)) &&
+			!opts.ExpiresAt.After(time.Now().Add(time.Hour))
+	})).Return("promo1", nil).Once()
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	body := `{"url":"` + testURL + `","ttl":"1h"}`
+	req, _ := http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	h.ShortenURL(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"expires_at"`)
+	svc.AssertExpectations(t)
+}
+
+func TestShortenURL_InvalidExpiry(t *testing.T) {
+	cases := map[string]string{
+		"оба поля сразу":    `{"url":"https://example.com","ttl":"1h","expires_at":"2030-01-01T00:00:00Z"}`,
+		"некорректный ttl":  `{"url":"https://example.com","ttl":"soon"}`,
+		"отрицательный ttl": `{"url":"https://example.com","ttl":"-5m"}`,
+		"некорректная дата": `{"url":"https://example.com","expires_at":"tomorrow"}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc := new(mocks.MockURLService)
+			log := new(mocks.MockLogger)
+			h := handler.NewHandler(svc, nil, nil, log)
+
+			log.On("Info", mock.Anything, mock.Anything).Maybe()
+			log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			req, _ := http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			h.ShortenURL(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			svc.AssertNotCalled(t, "Shorten", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestResolveURL_Expired(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	slug := "expired"
+	svc.On("Resolve", mock.Anything, slug, mock.Anything).Return(nil, service.ErrLinkExpired).Once()
+
+	log.On("Info", "Handling resolve request", mock.Anything).Once()
+	log.On("Warn", "Slug expired", mock.Anything).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req, _ := http.NewRequest(http.MethodGet, "/expired", nil)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: slug}}
+	c.Request = req
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+
+	svc.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestResolveURL_RepositoryNotFound(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	slug := "missing"
+	svc.On("Resolve", mock.Anything, slug, mock.Anything).Return(nil, repository.ErrNotFound).Once()
+
+	log.On("Info", "Handling resolve request", mock.Anything).Once()
+	log.On("Warn", "Slug not found", mock.Anything).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req, _ := http.NewRequest(http.MethodGet, "/missing", nil)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: slug}}
+	c.Request = req
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	svc.AssertExpectations(t)
+	log.AssertExpectations(t)
+}
+
+func TestShortenURL_AliasErrors(t *testing.T) {
+	cases := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "алиас занят", serviceErr: repository.ErrAlreadyExists, wantStatus: http.StatusConflict},
+		{name: "алиас зарезервирован", serviceErr: service.ErrAliasReserved, wantStatus: http.StatusBadRequest},
+		{name: "некорректный алиас", serviceErr: service.ErrInvalidAlias, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := new(mocks.MockURLService)
+			log := new(mocks.MockLogger)
+			h := handler.NewHandler(svc, nil, nil, log)
+
+			svc.On("Shorten", mock.Anything, "https://example.com", service.ShortenOptions{Alias: "spring-sale"}).
+				Return("", tc.serviceErr).Once()
+			log.On("Info", mock.Anything, mock.Anything).Maybe()
+			log.On("Warn", "Shorten request rejected", mock.Anything).Once()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			body := `{"url":"https://example.com","alias":"spring-sale"}`
+			req, _ := http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			c.Request = req
+
+			h.ShortenURL(c)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			svc.AssertExpectations(t)
+			log.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveURL_RecordsClick(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	clicks := new(mocks.MockClickRecorder)
+	h := handler.NewHandler(svc, nil, clicks, log)
+
+	svc.On("Resolve", mock.Anything, "abc123", mock.Anything).Return(permanentRedirect("https://go.dev"), nil).Once()
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	clicks.On("Record", mock.MatchedBy(func(e analytics.ClickEvent) bool {
+		return e.Slug == "abc123" &&
+			e.Referrer == "https://news.example" &&
+			e.UserAgent == "test-agent" &&
+			e.ClientIP != "" &&
+			!e.At.IsZero()
+	})).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	req, _ := http.NewRequest(http.MethodGet, "/abc123", nil)
+	req.Header.Set("Referer", "https://news.example")
+	req.Header.Set("User-Agent", "test-agent")
+	req.RemoteAddr = "203.0.113.5:1234"
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "abc123"}}
+	c.Request = req
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	clicks.AssertExpectations(t)
+}
+
+func TestResolveURL_NotFoundDoesNotRecordClick(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	clicks := new(mocks.MockClickRecorder)
+	h := handler.NewHandler(svc, nil, clicks, log)
+
+	svc.On("Resolve", mock.Anything, "missing", mock.Anything).Return(nil, repository.ErrNotFound).Once()
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodGet, "/missing", nil)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "missing"}}
+	c.Request = req
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	clicks.AssertNotCalled(t, "Record", mock.Anything)
+}
+
+func TestRegisterRoutes_RouteMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	reject := func(c *gin.Context) { c.AbortWithStatus(http.StatusTooManyRequests) }
+	svc.On("Resolve", mock.Anything, "abc123", mock.Anything).Return(permanentRedirect("https://example.com"), nil).Once()
+
+	r := gin.New()
+	handler.NewHandler(svc, nil, nil, log).
+		Use(handler.Middleware{Shorten: []gin.HandlerFunc{reject}}).
+		RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(`{"url":"https://example.com"}`))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/abc123", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	svc.AssertNotCalled(t, "Shorten", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShortenURL_RejectedByPolicy(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	svc.On("Shorten", mock.Anything, "http://169.254.169.254", service.ShortenOptions{}).Return("", &service.URLPolicyError{
+		Reason: service.URLPolicyPrivateAddress,
+		Detail: "host points to a private network",
+	}).Once()
+
+	r := gin.New()
+	handler.NewHandler(svc, nil, nil, log).RegisterRoutes(r)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(`{"url":"http://169.254.169.254"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.JSONEq(t, `{"error":"host points to a private network","reason":"private_address"}`, w.Body.String())
+}
+
+func TestResolveURL_StorageUnavailable(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	clicks := new(mocks.MockClickRecorder)
+	h := handler.NewHandler(svc, nil, clicks, log)
+
+	svc.On("Resolve", mock.Anything, "abc", mock.Anything).Return(nil, repository.ErrUnavailable).Once()
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(http.MethodGet, "/abc", nil)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "abc"}}
+	c.Request = req
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	clicks.AssertNotCalled(t, "Record", mock.Anything)
+	log.AssertNotCalled(t, "Error", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveURL_TemporaryRedirectNotCached(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	svc.On("Resolve", mock.Anything, "abc123", mock.Anything).
+		Return(&service.Redirect{URL: "https://go.dev", Status: http.StatusTemporaryRedirect}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "abc123"}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/abc123", nil)
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "https://go.dev", w.Header().Get("Location"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
+func TestShortenURL_RedirectType(t *testing.T) {
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	t.Run("тип передаётся в сервис", func(t *testing.T) {
+		svc := new(mocks.MockURLService)
+		h := handler.NewHandler(svc, nil, nil, log)
+		svc.On("Shorten", mock.Anything, "https://example.com", service.ShortenOptions{RedirectType: 308}).
+			Return("abc123", nil).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/shorten",
+			bytes.NewBufferString(`{"url":"https://example.com","redirect_type":308}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ShortenURL(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("недопустимый тип — 400", func(t *testing.T) {
+		svc := new(mocks.MockURLService)
+		h := handler.NewHandler(svc, nil, nil, log)
+		svc.On("Shorten", mock.Anything, "https://example.com", service.ShortenOptions{RedirectType: 303}).
+			Return("", service.ErrInvalidRedirectType).Once()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/shorten",
+			bytes.NewBufferString(`{"url":"https://example.com","redirect_type":303}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.ShortenURL(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "redirect type")
+	})
+}
+
+func TestShortenURL_UTM(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	svc.On("Shorten", mock.Anything, "https://example.com", service.ShortenOptions{
+		UTM:         &model.UTM{Source: "tg", Campaign: "spring"},
+		UTMTemplate: "newsletter",
+	}).Return("abc123", nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(
+		`{"url":"https://example.com","utm":{"source":"tg","campaign":"spring"},"utm_template":"newsletter"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	h.ShortenURL(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestResolveURL_RulesVaryByUserAgent(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	ua := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+	svc.On("Resolve", mock.Anything, "app", service.Visitor{UserAgent: ua}).
+		Return(&service.Redirect{URL: "https://apps.apple.com/app/id1", Status: http.StatusFound, VaryUserAgent: true}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "app"}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/app", nil)
+	c.Request.Header.Set("User-Agent", ua)
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://apps.apple.com/app/id1", w.Header().Get("Location"))
+	assert.Equal(t, "User-Agent", w.Header().Get("Vary"))
+	svc.AssertExpectations(t)
+}
+
+func TestShortenURL_Rules(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	h := handler.NewHandler(svc, nil, nil, log)
+	human := false
+
+	svc.On("Shorten", mock.Anything, "https://example.com", service.ShortenOptions{
+		Rules: []model.LinkRule{
+			{OS: "ios", URL: "https://apps.apple.com/app/id1"},
+			{Device: "desktop", Bot: &human, URL: "https://example.com/desktop"},
+		},
+	}).Return("abc123", nil).Once()
+
+	shorten := func(body string) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		h.ShortenURL(c)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, shorten(`{"url":"https://example.com","rules":[`+
+		`{"os":"ios","url":"https://apps.apple.com/app/id1"},`+
+		`{"device":"desktop","bot":false,"url":"https://example.com/desktop"}]}`))
+	assert.Equal(t, http.StatusBadRequest, shorten(`{"url":"https://example.com","rules":[{"os":"ios","url":"not a url"}]}`))
+	svc.AssertExpectations(t)
+}
+
+func TestResolveURL_StickyVariant(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	clicks := new(mocks.MockClickRecorder)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	h := handler.NewHandler(svc, nil, clicks, log)
+
+	svc.On("Resolve", mock.Anything, "split", mock.MatchedBy(func(v service.Visitor) bool {
+		return v.Variant == "b"
+	})).Return(&service.Redirect{
+		URL:           "https://example.com/b",
+		Status:        http.StatusFound,
+		Variant:       "b",
+		VariantMaxAge: time.Hour,
+	}, nil).Once()
+	clicks.On("Record", mock.MatchedBy(func(e analytics.ClickEvent) bool {
+		return e.Slug == "split" && e.Variant == "b"
+	})).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "split"}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/split", nil)
+	c.Request.AddCookie(&http.Cookie{Name: "sk_variant", Value: "b"})
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://example.com/b", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "sk_variant", cookies[0].Name)
+	assert.Equal(t, "b", cookies[0].Value)
+	assert.Equal(t, "/split", cookies[0].Path)
+	assert.Equal(t, 3600, cookies[0].MaxAge)
+	svc.AssertExpectations(t)
+	clicks.AssertExpectations(t)
+}
+
+func TestResolveURL_VariantWithoutStickyDoesNotSetCookie(t *testing.T) {
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	h := handler.NewHandler(svc, nil, nil, log)
+
+	svc.On("Resolve", mock.Anything, "split", service.Visitor{}).
+		Return(&service.Redirect{URL: "https://example.com/a", Status: http.StatusFound, Variant: "a"}, nil).Once()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{gin.Param{Key: "slug", Value: "split"}}
+	c.Request, _ = http.NewRequest(http.MethodGet, "/split", nil)
+
+	h.ResolveURL(c)
+
+	assert.Equal(t, "https://example.com/a", w.Header().Get("Location"))
+	assert.Empty(t, w.Result().Cookies())
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLink — GET /api/v1/links/:slug
+func (h *Handler) GetLink(c *gin.Context) {
+	slug := c.Param("slug")
+
+	link, err := h.service.GetLink(c.Request.Context(), auth.OwnerID(c), slug)
+	if err != nil {
+		h.respondLinkError(c, slug, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLinkResponse(link))
+}
+
+// UpdateLink — PATCH /api/v1/links/:slug
+func (h *Handler) UpdateLink(c *gin.Context) {
+	slug := c.Param("slug")
+
+	var req UpdateLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid update link request", map[string]interface{}{
+			"slug":  slug,
+			"error": err.Error(),
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	link, err := h.service.UpdateLink(c.Request.Context(), auth.OwnerID(c), slug, service.LinkUpdate{
+		URL:          req.URL,
+		Status:       req.Status,
+		RedirectType: req.RedirectType,
+
+		QueryPassthrough: req.QueryPassthrough,
+		PathPassthrough:  req.PathPassthrough,
+		UTM:              req.UTM.toModel(),
+		UTMTemplate:      req.UTMTemplate,
+
+		Rules: req.rules(),
+
+		Targets:       req.targets(),
+		StickyTargets: req.StickyTargets,
+	})
+	if err != nil {
+		h.respondLinkError(c, slug, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newLinkResponse(link))
+}
+
+// DeleteLink — DELETE /api/v1/links/:slug
+func (h *Handler) DeleteLink(c *gin.Context) {
+	slug := c.Param("slug")
+
+	if err := h.service.DeleteLink(c.Request.Context(), auth.OwnerID(c), slug); err != nil {
+		h.respondLinkError(c, slug, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondLinkError отвечает на ошибку сервиса в API управления ссылками.
+func (h *Handler) respondLinkError(c *gin.Context, slug string, err error) {
+	if status, body, ok := policyErrorResponse(err); ok {
+		c.JSON(status, body)
+		return
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+	case service.IsRejected(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, unavailableBody)
+	default:
+		h.logger.Error("Link management request failed", err, map[string]interface{}{
+			"slug":   slug,
+			"method": c.Request.Method,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+	}
+}
@@ -0,0 +1,230 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func setupLinksRouter() (*gin.Engine, *mocks.MockURLService, *mocks.MockLogger) {
+	gin.SetMode(gin.TestMode)
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	r := gin.New()
+	handler.NewHandler(svc, nil, nil, log).RegisterRoutes(r)
+	return r, svc, log
+}
+
+func TestGetLink(t *testing.T) {
+	r, svc, _ := setupLinksRouter()
+
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.On("GetLink", mock.Anything, "", "abc123").Return(&model.Link{
+		Slug:      "abc123",
+		URL:       "https://example.com",
+		Status:    model.LinkStatusActive,
+		CreatedAt: createdAt,
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/links/abc123", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"slug": "abc123",
+		"url": "https://example.com",
+		"status": "active",
+		"created_at": "2025-01-01T00:00:00Z"
+	}`, w.Body.String())
+	svc.AssertExpectations(t)
+}
+
+func TestGetLink_NotFound(t *testing.T) {
+	r, svc, _ := setupLinksRouter()
+
+	svc.On("GetLink", mock.Anything, "", "missing").Return(nil, repository.ErrNotFound).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/links/missing", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestUpdateLink(t *testing.T) {
+	r, svc, _ := setupLinksRouter()
+
+	newURL := "https://new.example.com"
+	status := model.LinkStatusDisabled
+	svc.On("UpdateLink", mock.Anything, "", "abc123", service.LinkUpdate{URL: &newURL, Status: &status}).
+		Return(&model.Link{Slug: "abc123", URL: newURL, Status: status}, nil).Once()
+
+	w := httptest.NewRecorder()
+	body := `{"url":"https://new.example.com","status":"disabled"}`
+	req, _ := http.NewRequest(http.MethodPatch, "/api/v1/links/abc123", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"disabled"`)
+	svc.AssertExpectations(t)
+}
+
+func TestUpdateLink_InvalidBody(t *testing.T) {
+	cases := map[string]string{
+		"неизвестный статус": `{"status":"paused"}`,
+		"некорректный URL":   `{"url":"not a url"}`,
+		"битый JSON":         `{"url":`,
+		"правило без адреса": `{"rules":[{"os":"ios"}]}`,
+		"вариант без веса":   `{"targets":[{"variant":"a","url":"https://example.com/a"}]}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, svc, _ := setupLinksRouter()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch, "/api/v1/links/abc123", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			svc.AssertNotCalled(t, "UpdateLink", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestDeleteLink(t *testing.T) {
+	r, svc, _ := setupLinksRouter()
+
+	svc.On("DeleteLink", mock.Anything, "", "abc123").Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/links/abc123", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestDeleteLink_ServiceError(t *testing.T) {
+	r, svc, log := setupLinksRouter()
+
+	svc.On("DeleteLink", mock.Anything, "", "abc123").Return(errors.New("db down")).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/links/abc123", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	log.AssertCalled(t, "Error", "Link management request failed", mock.Anything, mock.Anything)
+}
+
+func TestRegisterRoutes_ResolveStillReachable(t *testing.T) {
+	r, svc, _ := setupLinksRouter()
+
+	svc.On("Resolve", mock.Anything, "abc123", mock.Anything).Return(permanentRedirect("https://go.dev"), nil).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/abc123", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	svc.AssertExpectations(t)
+}
+
+func TestRegisterRoutes_ResolvePassthrough(t *testing.T) {
+	r, svc, _ := setupLinksRouter()
+
+	svc.On("Resolve", mock.Anything, "docs", mock.Anything).Return(&service.Redirect{
+		URL:              "https://example.com/docs?lang=en",
+		Status:           http.StatusFound,
+		QueryPassthrough: model.QueryPassthroughKeep,
+		PathPassthrough:  true,
+	}, nil).Once()
+	svc.On("Resolve", mock.Anything, "plain", mock.Anything).Return(&service.Redirect{
+		URL:    "https://example.com/",
+		Status: http.StatusFound,
+	}, nil).Twice()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/guide/intro?ref=newsletter&lang=de", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://example.com/docs/guide/intro?lang=en&ref=newsletter", w.Header().Get("Location"))
+
+	// Без передачи параметры посетителя отбрасываются, а хвост пути даёт 404, как до появления маршрута
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/plain?ref=newsletter", nil))
+	assert.Equal(t, "https://example.com/", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/plain/extra", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	svc.AssertExpectations(t)
+}
+
+func TestAPIKeyOwnerPassedToService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := new(mocks.MockURLService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	keys := mem.NewAPIKeyStore()
+	require.NoError(t, auth.Seed(context.Background(), keys, []string{"team-a:secret"}))
+
+	r := gin.New()
+	handler.NewHandler(svc, nil, nil, log).
+		Use(handler.Middleware{
+			Shorten: []gin.HandlerFunc{auth.Middleware(keys, false, log)},
+			Links:   []gin.HandlerFunc{auth.Middleware(keys, true, log)},
+		}).
+		RegisterRoutes(r)
+
+	svc.On("Shorten", mock.Anything, "https://example.com", service.ShortenOptions{OwnerID: "team-a"}).
+		Return("abc123", nil).Once()
+	svc.On("DeleteLink", mock.Anything, "team-a", "abc123").Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodPost, "/shorten", bytes.NewBufferString(`{"url":"https://example.com"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Управление ссылками без ключа недоступно
+	req, _ = http.NewRequest(http.MethodDelete, "/api/v1/links/abc123", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req.Header.Set("X-API-Key", "secret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	svc.AssertExpectations(t)
+}
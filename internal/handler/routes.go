@@ -3,6 +3,21 @@ package handler
 import "github.com/gin-gonic/gin"
 
 func (h *Handler) RegisterRoutes(r *gin.Engine) {
-	r.POST("/shorten", h.ShortenURL)
-	r.GET("/:slug", h.ResolveURL)
+	r.POST("/shorten", chain(h.middleware.Shorten, h.ShortenURL)...)
+	r.GET("/:slug", chain(h.middleware.Resolve, h.ResolveURL)...)
+	// Хвост пути после slug передаётся в адрес назначения, если ссылка это разрешает
+	r.GET("/:slug/*path", chain(h.middleware.Resolve, h.ResolveURL)...)
+
+	links := r.Group("/api/v1/links", h.middleware.Links...)
+	links.GET("/:slug", h.GetLink)
+	links.PATCH("/:slug", h.UpdateLink)
+	links.DELETE("/:slug", h.DeleteLink)
+	links.GET("/:slug/stats", h.GetLinkStats)
+}
+
+// chain возвращает цепочку из middleware и конечного обработчика, не изменяя исходный срез.
+func chain(middleware []gin.HandlerFunc, handler gin.HandlerFunc) []gin.HandlerFunc {
+	handlers := make([]gin.HandlerFunc, 0, len(middleware)+1)
+	handlers = append(handlers, middleware...)
+	return append(handlers, handler)
 }
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Параметры статистики по умолчанию
+const (
+	defaultStatsLimit       = 10
+	defaultHourlyStatsRange = 24 * time.Hour
+	defaultDailyStatsRange  = 7 * 24 * time.Hour
+)
+
+// GetLinkStats — GET /api/v1/links/:slug/stats?from=&to=&interval=hour|day&limit=
+func (h *Handler) GetLinkStats(c *gin.Context) {
+	slug := c.Param("slug")
+
+	q, err := parseStatsQuery(c, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats, err := h.stats.LinkStats(c.Request.Context(), auth.OwnerID(c), slug, q)
+	switch {
+	case errors.Is(err, service.ErrInvalidStatsQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	case errors.Is(err, repository.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, unavailableBody)
+		return
+	case err != nil:
+		h.logger.Error("Failed to load link stats", err, map[string]interface{}{
+			"slug": slug,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newLinkStatsResponse(slug, q, stats))
+}
+
+// parseStatsQuery читает параметры выборки из query string и подставляет значения по умолчанию.
+func parseStatsQuery(c *gin.Context, now time.Time) (model.StatsQuery, error) {
+	q := model.StatsQuery{
+		To:       now.UTC(),
+		Interval: c.DefaultQuery("interval", model.StatsIntervalDay),
+		Limit:    defaultStatsLimit,
+	}
+
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return q, errors.New("to must be an RFC 3339 timestamp")
+		}
+		q.To = to.UTC()
+	}
+
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return q, errors.New("from must be an RFC 3339 timestamp")
+		}
+		q.From = from.UTC()
+	} else if q.Interval == model.StatsIntervalHour {
+		q.From = q.To.Add(-defaultHourlyStatsRange)
+	} else {
+		q.From = q.To.Add(-defaultDailyStatsRange)
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return q, errors.New("limit must be an integer")
+		}
+		q.Limit = limit
+	}
+
+	return q, nil
+}
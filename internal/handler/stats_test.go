@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func setupStatsRouter() (*gin.Engine, *mocks.MockStatsService) {
+	gin.SetMode(gin.TestMode)
+	stats := new(mocks.MockStatsService)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	r := gin.New()
+	handler.NewHandler(new(mocks.MockURLService), stats, nil, log).RegisterRoutes(r)
+	return r, stats
+}
+
+func TestGetLinkStats(t *testing.T) {
+	r, stats := setupStatsRouter()
+
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 1, 2, 0, 0, 0, time.UTC)
+	q := model.StatsQuery{From: from, To: to, Interval: model.StatsIntervalHour, Limit: 3}
+
+	stats.On("LinkStats", mock.Anything, "", "abc123", q).Return(&model.LinkStats{
+		TotalClicks:    2,
+		UniqueVisitors: 1,
+		Series: []model.StatsBucket{
+			{Start: from, Clicks: 2, UniqueVisitors: 1},
+			{Start: from.Add(time.Hour)},
+		},
+		TopReferrers:  []model.StatsCount{{Value: "https://t.me", Clicks: 2}},
+		TopUserAgents: []model.StatsCount{{Value: "Chrome", Clicks: 2}},
+		Variants:      []model.StatsCount{{Value: "a", Clicks: 2}},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet,
+		"/api/v1/links/abc123/stats?from=2025-05-01T00:00:00Z&to=2025-05-01T02:00:00Z&interval=hour&limit=3", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"slug": "abc123",
+		"from": "2025-05-01T00:00:00Z",
+		"to": "2025-05-01T02:00:00Z",
+		"interval": "hour",
+		"total_clicks": 2,
+		"unique_visitors": 1,
+		"series": [
+			{"start": "2025-05-01T00:00:00Z", "clicks": 2, "unique_visitors": 1},
+			{"start": "2025-05-01T01:00:00Z", "clicks": 0, "unique_visitors": 0}
+		],
+		"top_referrers": [{"value": "https://t.me", "clicks": 2}],
+		"top_user_agents": [{"value": "Chrome", "clicks": 2}],
+		"variants": [{"value": "a", "clicks": 2}]
+	}`, w.Body.String())
+	stats.AssertExpectations(t)
+}
+
+func TestGetLinkStats_DefaultRange(t *testing.T) {
+	r, stats := setupStatsRouter()
+
+	stats.On("LinkStats", mock.Anything, "", "abc123", mock.MatchedBy(func(q model.StatsQuery) bool {
+		return q.Interval == model.StatsIntervalDay && q.Limit == 10 && q.To.Sub(q.From) == 7*24*time.Hour
+	})).Return(&model.LinkStats{}, nil).Once()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/links/abc123/stats", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	stats.AssertExpectations(t)
+}
+
+func TestGetLinkStats_Errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{name: "bad from", query: "?from=yesterday", status: http.StatusBadRequest},
+		{name: "bad limit", query: "?limit=ten", status: http.StatusBadRequest},
+		{name: "invalid query", query: "?interval=week", err: service.ErrInvalidStatsQuery, status: http.StatusBadRequest},
+		{name: "not found", err: repository.ErrNotFound, status: http.StatusNotFound},
+		{name: "storage unavailable", err: repository.ErrUnavailable, status: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, stats := setupStatsRouter()
+			if tc.err != nil {
+				stats.On("LinkStats", mock.Anything, "", "abc123", mock.Anything).Return(nil, tc.err).Once()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/api/v1/links/abc123/stats"+tc.query, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+			stats.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// variantCookie хранит вариант A/B-разбиения, закреплённый за посетителем. Путь cookie — сама
+// короткая ссылка, поэтому у каждой ссылки свой вариант.
+const variantCookie = "sk_variant"
+
+// variantFromCookie возвращает закреплённый за посетителем вариант; пусто — cookie нет.
+func variantFromCookie(c *gin.Context) string {
+	variant, err := c.Cookie(variantCookie)
+	if err != nil {
+		return ""
+	}
+	return variant
+}
+
+func setVariantCookie(c *gin.Context, slug, variant string, maxAge time.Duration) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     variantCookie,
+		Value:    variant,
+		Path:     "/" + slug,
+		MaxAge:   int(maxAge / time.Second),
+		Secure:   c.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
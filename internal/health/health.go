@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Состояния приложения для /readyz
+const (
+	StateStarting     = "starting"
+	StateReady        = "ready"
+	StateShuttingDown = "shutting_down"
+)
+
+// Статусы зависимостей
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // некритичная зависимость недоступна, сервис работает без неё
+	StatusDown     = "down"
+)
+
+// defaultTimeout — сколько ждать ответа каждой зависимости.
+const defaultTimeout = 2 * time.Second
+
+// CheckFunc проверяет доступность зависимости.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	critical bool
+}
+
+// Health обслуживает /healthz и /readyz. Приложение готово принимать трафик, когда оно
+// запущено, не останавливается и все критичные зависимости отвечают.
+//
+// /readyz открыт наружу, поэтому в ответ попадает только статус зависимости, а текст ошибки
+// (в ошибках pgx есть хост, пользователь и имя базы) пишется в лог.
+type Health struct {
+	checks  []check
+	timeout time.Duration
+	state   atomic.Value // string
+	logger  logger.Logger
+}
+
+// New создаёт проверки в состоянии «запускается». timeout <= 0 заменяется значением по умолчанию.
+func New(timeout time.Duration, log logger.Logger) *Health {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	h := &Health{timeout: timeout, logger: log}
+	h.state.Store(StateStarting)
+	return h
+}
+
+// AddCritical добавляет зависимость, без которой сервис не может обслуживать запросы.
+func (h *Health) AddCritical(name string, fn CheckFunc) {
+	h.checks = append(h.checks, check{name: name, fn: fn, critical: true})
+}
+
+// AddOptional добавляет зависимость, без которой сервис работает в деградированном режиме
+// (например, кэш): её сбой виден в ответе, но готовность не снимает.
+func (h *Health) AddOptional(name string, fn CheckFunc) {
+	h.checks = append(h.checks, check{name: name, fn: fn})
+}
+
+// SetState переключает состояние приложения: StateStarting, StateReady или StateShuttingDown.
+func (h *Health) SetState(state string) {
+	h.state.Store(state)
+}
+
+// State возвращает текущее состояние приложения.
+func (h *Health) State() string {
+	return h.state.Load().(string)
+}
+
+// Register добавляет маршруты /healthz и /readyz.
+func (h *Health) Register(r gin.IRoutes) {
+	r.GET("/healthz", h.Liveness)
+	r.GET("/readyz", h.Readiness)
+}
+
+// Liveness отвечает 200, пока процесс способен обрабатывать запросы. Зависимости не проверяются,
+// чтобы сбой базы не приводил к перезапуску живых инстансов.
+func (h *Health) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": StatusOK})
+}
+
+// ReadinessResponse — тело ответа /readyz.
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	State  string            `json:"state"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Readiness проверяет зависимости параллельно и отвечает 503, если приложение ещё запускается,
+// уже останавливается или недоступна критичная зависимость.
+func (h *Health) Readiness(c *gin.Context) {
+	state := h.State()
+	if state != StateReady {
+		c.JSON(http.StatusServiceUnavailable, ReadinessResponse{Status: StatusDown, State: state})
+		return
+	}
+
+	resp := ReadinessResponse{Status: StatusOK, State: state, Checks: h.run(c.Request.Context())}
+	httpStatus := http.StatusOK
+	for _, chk := range h.checks {
+		switch {
+		case resp.Checks[chk.name] == StatusOK:
+		case chk.critical:
+			resp.Status = StatusDown
+			httpStatus = http.StatusServiceUnavailable
+		case resp.Status == StatusOK:
+			resp.Status = StatusDegraded
+		}
+	}
+	c.JSON(httpStatus, resp)
+}
+
+// run выполняет все проверки с общим таймаутом и возвращает статус каждой зависимости.
+func (h *Health) run(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	results := make(map[string]string, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := StatusOK
+			if err := chk.fn(ctx); err != nil {
+				status = StatusDown
+				h.logger.Warn("Readiness check failed", map[string]interface{}{
+					"check":    chk.name,
+					"critical": chk.critical,
+					"error":    err.Error(),
+				})
+			}
+			mu.Lock()
+			results[chk.name] = status
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,102 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/health"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func serve(t *testing.T, h *health.Health, path string) (int, health.ReadinessResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h.Register(r)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp health.ReadinessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func ok(context.Context) error { return nil }
+
+func TestLiveness_IgnoresStateAndDependencies(t *testing.T) {
+	h := health.New(time.Second, &mocks.MockLogger{})
+	h.AddCritical("postgres", func(context.Context) error { return errors.New("down") })
+
+	code, resp := serve(t, h, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, resp.Status)
+}
+
+func TestReadiness_States(t *testing.T) {
+	h := health.New(time.Second, &mocks.MockLogger{})
+	h.AddCritical("postgres", ok)
+
+	code, resp := serve(t, h, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StateStarting, resp.State)
+
+	h.SetState(health.StateReady)
+	code, resp = serve(t, h, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusOK, resp.Status)
+	assert.Equal(t, map[string]string{"postgres": health.StatusOK}, resp.Checks)
+
+	h.SetState(health.StateShuttingDown)
+	code, resp = serve(t, h, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StateShuttingDown, resp.State)
+}
+
+func TestReadiness_OptionalFailureDegrades(t *testing.T) {
+	log := &mocks.MockLogger{}
+	h := health.New(time.Second, log)
+	h.AddCritical("postgres", ok)
+	h.AddOptional("redis", func(context.Context) error { return errors.New("connection refused") })
+	h.SetState(health.StateReady)
+	log.On("Warn", "Readiness check failed", mock.MatchedBy(func(f map[string]interface{}) bool {
+		return f["check"] == "redis" && f["error"] == "connection refused"
+	})).Once()
+
+	code, resp := serve(t, h, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusDegraded, resp.Status)
+	// Текст ошибки остаётся в логе и не попадает в ответ
+	assert.Equal(t, health.StatusDown, resp.Checks["redis"])
+	log.AssertExpectations(t)
+}
+
+func TestReadiness_CriticalTimeout(t *testing.T) {
+	log := &mocks.MockLogger{}
+	log.On("Warn", "Readiness check failed", mock.Anything).Once()
+	h := health.New(50*time.Millisecond, log)
+	h.AddCritical("postgres", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	h.AddOptional("redis", ok)
+	h.SetState(health.StateReady)
+
+	start := time.Now()
+	code, resp := serve(t, h, "/readyz")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDown, resp.Status)
+	assert.Equal(t, health.StatusDown, resp.Checks["postgres"])
+	assert.Equal(t, health.StatusOK, resp.Checks["redis"])
+}
@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+)
+
+// Результаты чтения из кэша — значения метки result
+const (
+	resultHit         = "hit"
+	resultNegativeHit = "negative_hit"
+	resultMiss        = "miss"
+	resultError       = "error"
+)
+
+// instrumentedCache считает результаты чтения из кэша.
+type instrumentedCache struct {
+	cache.URLCache
+	lookups *prometheus.CounterVec
+}
+
+// InstrumentCache оборачивает кэш счётчиком чтений по результату и регистрирует показатели
+// его слоёв: попадания по уровням двухуровневого кэша и состояние breaker.
+func InstrumentCache(next cache.URLCache, reg prometheus.Registerer) cache.URLCache {
+	c := &instrumentedCache{
+		URLCache: next,
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "lookups_total",
+			Help:      "Cache reads by result.",
+		}, []string{"result"}),
+	}
+	reg.MustRegister(c.lookups)
+	registerCacheLayers(reg, next)
+	return c
+}
+
+func (c *instrumentedCache) Get(ctx context.Context, slug string) (string, error) {
+	url, err := c.URLCache.Get(ctx, slug)
+	c.lookups.WithLabelValues(lookupResult(url, err)).Inc()
+	return url, err
+}
+
+// Unwrap возвращает обёрнутый кэш.
+func (c *instrumentedCache) Unwrap() cache.URLCache {
+	return c.URLCache
+}
+
+func lookupResult(url string, err error) string {
+	switch {
+	case err == nil && url == cache.NotFound:
+		return resultNegativeHit
+	case err == nil:
+		return resultHit
+	case errors.Is(err, cache.ErrCacheMiss):
+		return resultMiss
+	default:
+		return resultError
+	}
+}
+
+// registerCacheLayers проходит по цепочке обёрток кэша и регистрирует показатели каждого слоя.
+func registerCacheLayers(reg prometheus.Registerer, c cache.URLCache) {
+	for _, layer := range cache.Layers(c) {
+		if tiered, ok := layer.(interface{ Stats() cache.TieredStats }); ok {
+			registerTierStats(reg, tiered.Stats)
+		}
+		if guarded, ok := layer.(interface{ Breaker() *breaker.Breaker }); ok {
+			RegisterBreaker(reg, guarded.Breaker())
+		}
+	}
+}
+
+func registerTierStats(reg prometheus.Registerer, stats func() cache.TieredStats) {
+	desc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "cache", "tier_lookups_total"),
+		"Reads per tier of the two-tier cache by result.",
+		[]string{"tier", "result"}, nil,
+	)
+	reg.MustRegister(newCollectorFunc(func(ch chan<- prometheus.Metric) {
+		s := stats()
+		for _, tier := range []struct {
+			name  string
+			stats cache.TierStats
+		}{{"local", s.Local}, {"remote", s.Remote}} {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(tier.stats.Hits), tier.name, resultHit)
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(tier.stats.Misses), tier.name, resultMiss)
+		}
+	}, desc))
+}
+
+// RegisterRedisPool регистрирует статистику пула соединений Redis; name различает клиентов.
+func RegisterRedisPool(reg prometheus.Registerer, name string, stats func() *redis.PoolStats) {
+	labels := prometheus.Labels{"client": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "redis_pool", metric), help, nil, labels)
+	}
+	var (
+		hits     = desc("hits_total", "Times a free connection was found in the pool.")
+		misses   = desc("misses_total", "Times a free connection was not found in the pool.")
+		timeouts = desc("timeouts_total", "Times a wait for a connection timed out.")
+		total    = desc("connections", "Connections in the pool.")
+		idle     = desc("idle_connections", "Idle connections in the pool.")
+		stale    = desc("stale_connections_total", "Stale connections removed from the pool.")
+	)
+	reg.MustRegister(newCollectorFunc(func(ch chan<- prometheus.Metric) {
+		s := stats()
+		ch <- prometheus.MustNewConstMetric(hits, prometheus.CounterValue, float64(s.Hits))
+		ch <- prometheus.MustNewConstMetric(misses, prometheus.CounterValue, float64(s.Misses))
+		ch <- prometheus.MustNewConstMetric(timeouts, prometheus.CounterValue, float64(s.Timeouts))
+		ch <- prometheus.MustNewConstMetric(total, prometheus.GaugeValue, float64(s.TotalConns))
+		ch <- prometheus.MustNewConstMetric(idle, prometheus.GaugeValue, float64(s.IdleConns))
+		ch <- prometheus.MustNewConstMetric(stale, prometheus.CounterValue, float64(s.StaleConns))
+	}, hits, misses, timeouts, total, idle, stale))
+}
+
+// RegisterBreaker регистрирует состояние и счётчики circuit breaker.
+func RegisterBreaker(reg prometheus.Registerer, b *breaker.Breaker) {
+	labels := prometheus.Labels{"breaker": b.Name()}
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "breaker",
+			Name:        "state",
+			Help:        "Circuit breaker state: 0 closed, 1 open, 2 half-open.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(b.State()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "breaker",
+			Name:        "opens_total",
+			Help:        "Times the circuit breaker opened.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(b.Stats().Opens) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Subsystem:   "breaker",
+			Name:        "rejected_total",
+			Help:        "Calls rejected while the circuit breaker was open.",
+			ConstLabels: labels,
+		}, func() float64 { return float64(b.Stats().Rejected) }),
+	)
+}
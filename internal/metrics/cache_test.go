@@ -0,0 +1,59 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/metrics"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+func TestInstrumentCache(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	remote := new(mocks.MockCache)
+	log := logger.InitLogger(&config.Config{LogLevel: "error"})
+	guarded := cache.NewFailSafeCache(remote, breaker.New("redis", breaker.Settings{Threshold: 5}, log))
+	c := metrics.InstrumentCache(cache.NewTieredCache(cache.NewLRUCache(10, time.Minute), guarded), reg)
+	ctx := context.Background()
+
+	remote.On("Get", mock.Anything, "hot").Return("https://hot.example/", nil).Once()
+	remote.On("Get", mock.Anything, "nope").Return(cache.NotFound, nil).Once()
+	remote.On("Get", mock.Anything, "cold").Return("", cache.ErrCacheMiss).Once()
+	remote.On("Get", mock.Anything, "down").Return("", errors.New("redis down")).Once()
+
+	for _, slug := range []string{"hot", "hot", "nope", "cold", "down"} {
+		_, _ = c.Get(ctx, slug)
+	}
+
+	// Сбой Redis FailSafeCache превращает в промах
+	expected := `
+# HELP slugkiller_cache_lookups_total Cache reads by result.
+# TYPE slugkiller_cache_lookups_total counter
+slugkiller_cache_lookups_total{result="hit"} 2
+slugkiller_cache_lookups_total{result="miss"} 2
+slugkiller_cache_lookups_total{result="negative_hit"} 1
+# HELP slugkiller_cache_tier_lookups_total Reads per tier of the two-tier cache by result.
+# TYPE slugkiller_cache_tier_lookups_total counter
+slugkiller_cache_tier_lookups_total{result="hit",tier="local"} 1
+slugkiller_cache_tier_lookups_total{result="hit",tier="remote"} 2
+slugkiller_cache_tier_lookups_total{result="miss",tier="local"} 4
+slugkiller_cache_tier_lookups_total{result="miss",tier="remote"} 2
+# HELP slugkiller_breaker_state Circuit breaker state: 0 closed, 1 open, 2 half-open.
+# TYPE slugkiller_breaker_state gauge
+slugkiller_breaker_state{breaker="redis"} 0
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"slugkiller_cache_lookups_total", "slugkiller_cache_tier_lookups_total", "slugkiller_breaker_state"))
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// collectorFunc — коллектор, который снимает показатели одним вызовом при каждом опросе.
+type collectorFunc struct {
+	collect func(ch chan<- prometheus.Metric)
+	descs   []*prometheus.Desc
+}
+
+func newCollectorFunc(collect func(ch chan<- prometheus.Metric), descs ...*prometheus.Desc) prometheus.Collector {
+	return &collectorFunc{collect: collect, descs: descs}
+}
+
+func (c *collectorFunc) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range c.descs {
+		ch <- d
+	}
+}
+
+func (c *collectorFunc) Collect(ch chan<- prometheus.Metric) {
+	c.collect(ch)
+}
+
+// PgxPoolStatter отдаёт статистику пула соединений PostgreSQL.
+type PgxPoolStatter interface {
+	PoolStat() *pgxpool.Stat
+}
+
+// RegisterPgxPool регистрирует статистику пула соединений PostgreSQL.
+func RegisterPgxPool(reg prometheus.Registerer, pool PgxPoolStatter) {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pg_pool", metric), help, nil, nil)
+	}
+	var (
+		acquired        = desc("acquired_connections", "Connections currently in use.")
+		idle            = desc("idle_connections", "Idle connections in the pool.")
+		total           = desc("connections", "Connections in the pool.")
+		maxConns        = desc("max_connections", "Maximum size of the pool.")
+		acquires        = desc("acquires_total", "Successful connection acquisitions.")
+		acquireDuration = desc("acquire_duration_seconds_total", "Total time spent waiting for a connection.")
+		emptyAcquires   = desc("empty_acquires_total", "Acquisitions that had to wait because the pool was empty.")
+		canceled        = desc("canceled_acquires_total", "Acquisitions canceled by the caller's context.")
+	)
+	reg.MustRegister(newCollectorFunc(func(ch chan<- prometheus.Metric) {
+		s := pool.PoolStat()
+		ch <- prometheus.MustNewConstMetric(acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+		ch <- prometheus.MustNewConstMetric(idle, prometheus.GaugeValue, float64(s.IdleConns()))
+		ch <- prometheus.MustNewConstMetric(total, prometheus.GaugeValue, float64(s.TotalConns()))
+		ch <- prometheus.MustNewConstMetric(maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+		ch <- prometheus.MustNewConstMetric(acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+		ch <- prometheus.MustNewConstMetric(acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+		ch <- prometheus.MustNewConstMetric(emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+		ch <- prometheus.MustNewConstMetric(canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	}, acquired, idle, total, maxConns, acquires, acquireDuration, emptyAcquires, canceled))
+}
+
+// SlugPoolStatter — показатели пула заранее сгенерированных slug.
+type SlugPoolStatter interface {
+	Depth() int
+	Misses() uint64
+}
+
+// RegisterSlugPool регистрирует заполненность пула slug и число промахов мимо буфера.
+func RegisterSlugPool(reg prometheus.Registerer, pool SlugPoolStatter) {
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "slug_pool",
+			Name:      "depth",
+			Help:      "Pre-generated slugs buffered in memory.",
+		}, func() float64 { return float64(pool.Depth()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "slug_pool",
+			Name:      "misses_total",
+			Help:      "Slug requests served without the buffer because it was empty.",
+		}, func() float64 { return float64(pool.Misses()) }),
+	)
+}
+
+// ClickRecorderStatter — показатели очереди событий переходов.
+type ClickRecorderStatter interface {
+	Dropped() uint64
+	QueueLen() int
+}
+
+// RegisterClickRecorder регистрирует длину очереди кликов и число отброшенных событий.
+func RegisterClickRecorder(reg prometheus.Registerer, rec ClickRecorderStatter) {
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "clicks",
+			Name:      "queue_length",
+			Help:      "Click events waiting to be written.",
+		}, func() float64 { return float64(rec.QueueLen()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "clicks",
+			Name:      "dropped_total",
+			Help:      "Click events dropped because the queue was full.",
+		}, func() float64 { return float64(rec.Dropped()) }),
+	)
+}
@@ -0,0 +1,43 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/metrics"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := metrics.NewRegistry()
+	r := gin.New()
+	r.Use(metrics.HTTPMiddleware(reg))
+	r.GET("/:slug", func(c *gin.Context) { c.Status(http.StatusFound) })
+	r.GET("/metrics", gin.WrapH(metrics.Handler(reg)))
+
+	for _, path := range []string{"/abc", "/def", "/a/b/c"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Запросы группируются по шаблону маршрута, а не по конкретному пути
+	expected := `
+# HELP slugkiller_http_requests_total HTTP requests by route, method and status.
+# TYPE slugkiller_http_requests_total counter
+slugkiller_http_requests_total{method="GET",route="/:slug",status="302"} 2
+slugkiller_http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "slugkiller_http_requests_total"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "slugkiller_http_request_duration_seconds_bucket")
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace — общий префикс всех метрик сервиса.
+const namespace = "slugkiller"
+
+// NewRegistry создаёт реестр со стандартными метриками рантайма Go и процесса.
+// Отдельный реестр вместо глобального позволяет тестам и нескольким приложениям не мешать друг другу.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// Handler отдаёт метрики реестра в формате Prometheus.
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+// Исходы операций сервиса — значения метки outcome
+const (
+	outcomeOK          = "ok"
+	outcomeRejected    = "rejected"
+	outcomeConflict    = "conflict"
+	outcomeNotFound    = "not_found"
+	outcomeDisabled    = "disabled"
+	outcomeExpired     = "expired"
+	outcomeUnavailable = "unavailable"
+	outcomeError       = "error"
+)
+
+// instrumentedService считает исходы сокращения и редиректа; остальные методы
+// передаются сервису без изменений.
+type instrumentedService struct {
+	service.URLService
+	shorten *prometheus.CounterVec
+	resolve *prometheus.CounterVec
+}
+
+// InstrumentURLService оборачивает сервис ссылок счётчиками исходов Shorten и Resolve.
+func InstrumentURLService(next service.URLService, reg prometheus.Registerer) service.URLService {
+	s := &instrumentedService{
+		URLService: next,
+		shorten: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "shorten_total",
+			Help:      "Shorten calls by outcome.",
+		}, []string{"outcome"}),
+		resolve: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "resolve_total",
+			Help:      "Resolve calls by outcome.",
+		}, []string{"outcome"}),
+	}
+	reg.MustRegister(s.shorten, s.resolve)
+	return s
+}
+
+func (s *instrumentedService) Shorten(ctx context.Context, originalURL string, opts service.ShortenOptions) (string, error) {
+	slug, err := s.URLService.Shorten(ctx, originalURL, opts)
+	s.shorten.WithLabelValues(shortenOutcome(err)).Inc()
+	return slug, err
+}
+
+func (s *instrumentedService) Resolve(ctx context.Context, slug string, v service.Visitor) (*service.Redirect, error) {
+	r, err := s.URLService.Resolve(ctx, slug, v)
+	s.resolve.WithLabelValues(resolveOutcome(err)).Inc()
+	return r, err
+}
+
+func shortenOutcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case service.IsRejected(err):
+		return outcomeRejected
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return outcomeConflict
+	case errors.Is(err, repository.ErrUnavailable):
+		return outcomeUnavailable
+	default:
+		return outcomeError
+	}
+}
+
+func resolveOutcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, repository.ErrNotFound):
+		return outcomeNotFound
+	case errors.Is(err, service.ErrLinkDisabled):
+		return outcomeDisabled
+	case errors.Is(err, service.ErrLinkExpired):
+		return outcomeExpired
+	case errors.Is(err, repository.ErrUnavailable):
+		return outcomeUnavailable
+	default:
+		return outcomeError
+	}
+}
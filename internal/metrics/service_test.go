@@ -0,0 +1,58 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/metrics"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestInstrumentURLService(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	next := new(mocks.MockURLService)
+	svc := metrics.InstrumentURLService(next, reg)
+	ctx := context.Background()
+
+	next.On("Shorten", mock.Anything, "https://ok.example/", mock.Anything).Return("abc", nil)
+	next.On("Shorten", mock.Anything, "javascript:alert(1)", mock.Anything).
+		Return("", &service.URLPolicyError{Reason: service.URLPolicySchemeNotAllowed})
+	next.On("Shorten", mock.Anything, "https://taken.example/", mock.Anything).Return("", repository.ErrAlreadyExists)
+	next.On("Resolve", mock.Anything, "abc", mock.Anything).Return(&service.Redirect{URL: "https://ok.example/", Status: 302}, nil)
+	next.On("Resolve", mock.Anything, "gone", mock.Anything).Return(nil, service.ErrLinkExpired)
+	next.On("Resolve", mock.Anything, "nope", mock.Anything).Return(nil, repository.ErrNotFound)
+	next.On("Resolve", mock.Anything, "down", mock.Anything).Return(nil, repository.ErrUnavailable)
+	next.On("Resolve", mock.Anything, "boom", mock.Anything).Return(nil, errors.New("boom"))
+
+	for _, url := range []string{"https://ok.example/", "javascript:alert(1)", "https://taken.example/"} {
+		_, _ = svc.Shorten(ctx, url, service.ShortenOptions{})
+	}
+	for _, slug := range []string{"abc", "abc", "gone", "nope", "down", "boom"} {
+		_, _ = svc.Resolve(ctx, slug, service.Visitor{})
+	}
+
+	expected := `
+# HELP slugkiller_resolve_total Resolve calls by outcome.
+# TYPE slugkiller_resolve_total counter
+slugkiller_resolve_total{outcome="error"} 1
+slugkiller_resolve_total{outcome="expired"} 1
+slugkiller_resolve_total{outcome="not_found"} 1
+slugkiller_resolve_total{outcome="ok"} 2
+slugkiller_resolve_total{outcome="unavailable"} 1
+# HELP slugkiller_shorten_total Shorten calls by outcome.
+# TYPE slugkiller_shorten_total counter
+slugkiller_shorten_total{outcome="conflict"} 1
+slugkiller_shorten_total{outcome="ok"} 1
+slugkiller_shorten_total{outcome="rejected"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
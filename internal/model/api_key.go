@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// APIKey — ключ доступа к API. Сам ключ не хранится: только его SHA-256 в KeyHash.
+type APIKey struct {
+	ID        int64
+	OwnerID   string // кому принадлежат ссылки, созданные с этим ключом; у владельца может быть несколько ключей
+	Name      string // произвольное описание, например «ci» или «marketing-site»
+	KeyHash   string
+	CreatedAt time.Time
+	RevokedAt *time.Time // nil — ключ действует
+}
+
+// IsRevoked сообщает, отозван ли ключ.
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
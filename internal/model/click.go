@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Click — одно срабатывание короткой ссылки.
+type Click struct {
+	Slug      string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	UAFamily  string // семейство клиента, вычисленное из UserAgent
+	IPHash    string // хеш IP-адреса клиента; сам адрес не храним
+	Variant   string // вариант A/B-разбиения, на который ушёл клиент; пусто — разбиения не было
+}
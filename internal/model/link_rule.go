@@ -0,0 +1,20 @@
+package model
+
+// MaxLinkRules — сколько правил перехода можно задать одной ссылке.
+const MaxLinkRules = 20
+
+// LinkRule — правило перехода: клиент, подходящий под все заданные условия, уходит на URL
+// вместо адреса назначения ссылки. Правила проверяются по порядку, срабатывает первое подходящее.
+type LinkRule struct {
+	OS     string `json:"os,omitempty"`     // семейство ОС (useragent.OS*); пусто — любая
+	Device string `json:"device,omitempty"` // класс устройства (useragent.Device*); пусто — любой
+	Bot    *bool  `json:"bot,omitempty"`    // true — только роботы, false — только люди, nil — все
+	URL    string `json:"url"`
+}
+
+// Equal сообщает, что правила совпадают по условиям и адресу.
+func (r LinkRule) Equal(other LinkRule) bool {
+	sameBot := r.Bot == nil && other.Bot == nil ||
+		r.Bot != nil && other.Bot != nil && *r.Bot == *other.Bot
+	return sameBot && r.OS == other.OS && r.Device == other.Device && r.URL == other.URL
+}
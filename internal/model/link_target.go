@@ -0,0 +1,15 @@
+package model
+
+// MaxLinkTargets — сколько вариантов адреса назначения можно задать одной ссылке.
+const MaxLinkTargets = 10
+
+// MaxVariantLength — предельная длина имени варианта (колонка clicks.variant).
+const MaxVariantLength = 64
+
+// LinkTarget — вариант адреса назначения в A/B-разбиении: посетитель попадает на URL
+// с вероятностью Weight / сумма весов всех вариантов ссылки.
+type LinkTarget struct {
+	Variant string `json:"variant"` // имя варианта; записывается в клики и sticky-cookie
+	URL     string `json:"url"`
+	Weight  int    `json:"weight"`
+}
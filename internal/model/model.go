@@ -2,9 +2,91 @@ package model
 
 import "time"
 
+// Статусы ссылки
+const (
+	LinkStatusActive   = "active"
+	LinkStatusDisabled = "disabled"
+)
+
+// Типы перенаправления — HTTP-статус ответа на переход по ссылке
+const (
+	RedirectMovedPermanently  = 301
+	RedirectFound             = 302
+	RedirectTemporaryRedirect = 307
+	RedirectPermanentRedirect = 308
+)
+
+// Режимы передачи параметров запроса посетителя в адрес назначения; различаются тем,
+// что делать с параметром, который уже есть в адресе назначения
+const (
+	QueryPassthroughOff      = ""         // параметры посетителя отбрасываются
+	QueryPassthroughKeep     = "keep"     // остаётся значение из адреса назначения
+	QueryPassthroughOverride = "override" // значение посетителя заменяет значение из адреса назначения
+	QueryPassthroughAppend   = "append"   // сохраняются оба значения
+)
+
 type Link struct {
-	ID        int64
-	Slug      string
-	URL       string
-	CreatedAt time.Time
+	ID           int64
+	Slug         string
+	URL          string
+	Status       string
+	CanonicalURL string // нормализованный URL для поиска дублей; редирект идёт на URL
+	OwnerID      string // владелец API-ключа, создавшего ссылку; пусто — ссылка создана анонимно
+	RedirectType int    // один из Redirect*; 0 — тип по умолчанию из конфигурации
+	CreatedAt    time.Time
+	ExpiresAt    *time.Time // nil — ссылка бессрочная
+
+	QueryPassthrough string // один из QueryPassthrough*
+	PathPassthrough  bool   // дописывать хвост пути после slug к пути адреса назначения
+
+	UTM         *UTM   // UTM-метки ссылки; nil — не заданы
+	UTMTemplate string // имя шаблона UTM из конфигурации; метки UTM ссылки переопределяют метки шаблона
+
+	Rules []LinkRule // правила перехода по устройству клиента; URL — адрес для остальных
+
+	Targets       []LinkTarget // варианты адреса назначения с весами; если заданы, заменяют URL
+	StickyTargets bool         // закреплять выбранный вариант за посетителем через cookie
+}
+
+// IsActive сообщает, включена ли ссылка. Пустой статус считается активным.
+func (l *Link) IsActive() bool {
+	return l.Status == "" || l.Status == LinkStatusActive
+}
+
+// IsExpired сообщает, истёк ли срок жизни ссылки к моменту now.
+func (l *Link) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+// DedupeKey возвращает ключ поиска дублей: канонический URL, а если он не задан — исходный.
+func (l *Link) DedupeKey() string {
+	if l.CanonicalURL != "" {
+		return l.CanonicalURL
+	}
+	return l.URL
+}
+
+// IsValidRedirectType сообщает, можно ли использовать code как тип перенаправления.
+func IsValidRedirectType(code int) bool {
+	switch code {
+	case RedirectMovedPermanently, RedirectFound, RedirectTemporaryRedirect, RedirectPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsPermanentRedirect сообщает, что браузер вправе запомнить перенаправление.
+func IsPermanentRedirect(code int) bool {
+	return code == RedirectMovedPermanently || code == RedirectPermanentRedirect
+}
+
+// IsValidQueryPassthrough сообщает, известен ли режим передачи параметров запроса.
+func IsValidQueryPassthrough(mode string) bool {
+	switch mode {
+	case QueryPassthroughOff, QueryPassthroughKeep, QueryPassthroughOverride, QueryPassthroughAppend:
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Шаг временного ряда статистики
+const (
+	StatsIntervalHour = "hour"
+	StatsIntervalDay  = "day"
+)
+
+// StatsQuery — параметры выборки статистики: полуинтервал [From, To).
+type StatsQuery struct {
+	From     time.Time
+	To       time.Time
+	Interval string // StatsIntervalHour или StatsIntervalDay
+	Limit    int    // размер топов (рефереры, семейства клиентов)
+}
+
+// LinkStats — агрегированная статистика переходов по ссылке.
+type LinkStats struct {
+	TotalClicks    int64
+	UniqueVisitors int64
+	Series         []StatsBucket
+	TopReferrers   []StatsCount
+	TopUserAgents  []StatsCount
+	Variants       []StatsCount // переходы по вариантам A/B-разбиения
+}
+
+// StatsBucket — одна точка временного ряда.
+type StatsBucket struct {
+	Start          time.Time
+	Clicks         int64
+	UniqueVisitors int64
+}
+
+// StatsCount — значение и число переходов с ним.
+type StatsCount struct {
+	Value  string
+	Clicks int64
+}
+
+// IntervalDuration возвращает длительность шага временного ряда.
+func IntervalDuration(interval string) time.Duration {
+	if interval == StatsIntervalHour {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
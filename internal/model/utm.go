@@ -0,0 +1,54 @@
+package model
+
+// UTM — набор UTM-меток, которые сервис дописывает к адресу назначения при переходе.
+// Пустое поле означает, что метка не задана.
+type UTM struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// IsZero сообщает, что ни одна метка не задана.
+func (u UTM) IsZero() bool {
+	return u == UTM{}
+}
+
+// Override возвращает набор, в котором заданные в other метки заменяют метки u.
+func (u UTM) Override(other UTM) UTM {
+	if other.Source != "" {
+		u.Source = other.Source
+	}
+	if other.Medium != "" {
+		u.Medium = other.Medium
+	}
+	if other.Campaign != "" {
+		u.Campaign = other.Campaign
+	}
+	if other.Term != "" {
+		u.Term = other.Term
+	}
+	if other.Content != "" {
+		u.Content = other.Content
+	}
+	return u
+}
+
+// Params возвращает заданные метки как пары параметр–значение в каноническом порядке.
+func (u UTM) Params() [][2]string {
+	all := [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	}
+	params := all[:0]
+	for _, p := range all {
+		if p[1] != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+)
+
+// ErrUnavailable — лимитер сейчас не опрашивается или не ответил; запрос пропускается без лимита.
+var ErrUnavailable = errors.New("rate limiter unavailable")
+
+// failSafeTimeout — сколько ждать ответа Redis: лимит не должен заметно задерживать запрос.
+const failSafeTimeout = 200 * time.Millisecond
+
+// FailSafeLimiter пропускает обращения к лимитеру через circuit breaker, как FailSafeCache кэша:
+// пока breaker открыт, Redis не опрашивается, а любой сбой возвращается как ErrUnavailable.
+// Переходы состояния логирует сам breaker, поэтому сбой Redis не пишет в лог на каждый запрос.
+type FailSafeLimiter struct {
+	next    Limiter
+	breaker *breaker.Breaker
+}
+
+var _ Limiter = (*FailSafeLimiter)(nil)
+
+func NewFailSafeLimiter(next Limiter, b *breaker.Breaker) *FailSafeLimiter {
+	return &FailSafeLimiter{next: next, breaker: b}
+}
+
+func (l *FailSafeLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if l.breaker.Allow() != nil {
+		return Result{}, ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, failSafeTimeout)
+	defer cancel()
+
+	res, err := l.next.Allow(ctx, key, limit)
+	switch {
+	case err == nil:
+		l.breaker.Success()
+		return res, nil
+	case errors.Is(err, context.Canceled):
+		l.breaker.Release()
+	default:
+		l.breaker.Failure(err)
+	}
+	return Result{}, ErrUnavailable
+}
+
+// Breaker возвращает breaker, который следит за лимитером.
+func (l *FailSafeLimiter) Breaker() *breaker.Breaker {
+	return l.breaker
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit — не больше Requests запросов за Window. Всплеск до Requests запросов
+// допускается, дальше запросы пропускаются равномерно, раз в Window/Requests.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Enabled сообщает, задан ли лимит.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Window > 0
+}
+
+// interval — время, за которое восстанавливается одна единица квоты.
+func (l Limit) interval() time.Duration {
+	return l.Window / time.Duration(l.Requests)
+}
+
+// Result — решение лимитера по одному запросу.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // через сколько можно повторить отклонённый запрос
+	ResetAfter time.Duration // через сколько квота восстановится полностью
+}
+
+// Limiter считает запросы по ключу. Обе реализации используют алгоритм GCRA
+// (эквивалент token bucket), поэтому ведут себя одинаково.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// gcra применяет запрос к сохранённому теоретическому времени прихода (TAT)
+// и возвращает решение вместе с новым TAT.
+func gcra(now, tat time.Time, limit Limit) (Result, time.Time) {
+	interval := limit.interval()
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-limit.Window)
+	if now.Before(allowAt) {
+		return Result{
+			Limit:      limit.Requests,
+			RetryAfter: allowAt.Sub(now),
+			ResetAfter: tat.Sub(now),
+		}, tat
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      limit.Requests,
+		Remaining:  int(now.Sub(allowAt) / interval),
+		ResetAfter: newTAT.Sub(now),
+	}, newTAT
+}
@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery — раз в сколько вызовов Allow удалять ключи с полностью восстановленной квотой.
+const sweepEvery = 1024
+
+// MemoryLimiter хранит состояние в памяти процесса. Лимиты действуют в пределах
+// одного инстанса, поэтому он подходит для запуска без Redis.
+type MemoryLimiter struct {
+	mu    sync.Mutex
+	tats  map[string]time.Time
+	calls int
+	now   func() time.Time
+}
+
+var _ Limiter = (*MemoryLimiter)(nil)
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+func (m *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	res, tat := gcra(now, m.tats[key], limit)
+	m.tats[key] = tat
+
+	m.calls++
+	if m.calls%sweepEvery == 0 {
+		for k, t := range m.tats {
+			if !t.After(now) {
+				delete(m.tats, k)
+			}
+		}
+	}
+	return res, nil
+}
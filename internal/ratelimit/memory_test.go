@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+	limit := Limit{Requests: 3, Window: time.Minute}
+	ctx := context.Background()
+
+	// Всплеск до лимита проходит, остаток уменьшается
+	for want := 2; want >= 0; want-- {
+		res, err := l.Allow(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, want, res.Remaining)
+	}
+
+	res, _ := l.Allow(ctx, "ip:1", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 20*time.Second, res.RetryAfter)
+	assert.Equal(t, time.Minute, res.ResetAfter)
+
+	// Другой ключ считается отдельно
+	res, _ = l.Allow(ctx, "ip:2", limit)
+	assert.True(t, res.Allowed)
+
+	// Через интервал восстанавливается одна единица квоты
+	now = now.Add(20 * time.Second)
+	res, _ = l.Allow(ctx, "ip:1", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	res, _ = l.Allow(ctx, "ip:1", limit)
+	assert.False(t, res.Allowed)
+}
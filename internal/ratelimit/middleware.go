@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/auth"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc выделяет из запроса ключ, по которому считается лимит.
+type KeyFunc func(c *gin.Context) string
+
+// ByClientIP считает запросы по IP клиента.
+func ByClientIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByAPIKeyOrClientIP считает запросы по хэшу предъявленного API-ключа, а без ключа — по IP клиента.
+// Ключ здесь не проверяется: лимит срабатывает до аутентификации и не ходит в базу.
+func ByAPIKeyOrClientIP(c *gin.Context) string {
+	if key := auth.KeyFromRequest(c.Request); key != "" {
+		return "key:" + auth.HashKey(key)
+	}
+	return ByClientIP(c)
+}
+
+// Middleware ограничивает частоту запросов. name отделяет счётчики разных маршрутов.
+// Если лимитер недоступен, запрос пропускается: лучше временно остаться без лимита,
+// чем отказать всем клиентам. ErrUnavailable не логируется — о сбое сообщает breaker FailSafeLimiter.
+func Middleware(l Limiter, name string, limit Limit, key KeyFunc, log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := l.Allow(c.Request.Context(), name+":"+key(c), limit)
+		if errors.Is(err, ErrUnavailable) {
+			c.Next()
+			return
+		}
+		if err != nil {
+			log.Warn("Rate limiter unavailable, request allowed", map[string]interface{}{
+				"limit": name,
+				"error": err.Error(),
+			})
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", seconds(res.ResetAfter))
+
+		if !res.Allowed {
+			h.Set("Retry-After", seconds(res.RetryAfter))
+			log.Warn("Rate limit exceeded", map[string]interface{}{
+				"limit": name,
+				"ip":    c.ClientIP(),
+				"path":  c.Request.URL.Path,
+			})
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// seconds округляет длительность вверх до целых секунд, как того требуют заголовки.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
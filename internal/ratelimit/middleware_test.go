@@ -0,0 +1,127 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/ratelimit"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis down")
+}
+
+func setupRouter(l ratelimit.Limiter, limit ratelimit.Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := new(mocks.MockLogger)
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	r := gin.New()
+	r.GET("/x", ratelimit.Middleware(l, "test", limit, ratelimit.ByClientIP, log), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func doRequest(r *gin.Engine, ip string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/x", nil)
+	req.RemoteAddr = ip + ":1234"
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_LimitsByIP(t *testing.T) {
+	r := setupRouter(ratelimit.NewMemoryLimiter(), ratelimit.Limit{Requests: 2, Window: time.Minute})
+
+	w := doRequest(r, "10.0.0.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusOK, doRequest(r, "10.0.0.1").Code)
+
+	w = doRequest(r, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, doRequest(r, "10.0.0.2").Code)
+}
+
+func TestMiddleware_LimitsByAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := new(mocks.MockLogger)
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	r := gin.New()
+	r.GET("/x", ratelimit.Middleware(ratelimit.NewMemoryLimiter(), "test", ratelimit.Limit{Requests: 1, Window: time.Minute},
+		ratelimit.ByAPIKeyOrClientIP, log), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(ip string, header ...string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/x", nil)
+		req.RemoteAddr = ip + ":1234"
+		if len(header) == 2 {
+			req.Header.Set(header[0], header[1])
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Один ключ делит лимит между адресами, а Bearer и X-API-Key дают один и тот же счётчик
+	assert.Equal(t, http.StatusOK, do("10.0.0.1", "Authorization", "Bearer k1"))
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.2", "X-API-Key", "k1"))
+
+	// Другой ключ и запрос без ключа считаются отдельно
+	assert.Equal(t, http.StatusOK, do("10.0.0.1", "X-API-Key", "k2"))
+	assert.Equal(t, http.StatusOK, do("10.0.0.1"))
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.1"))
+}
+
+func TestMiddleware_FailsOpen(t *testing.T) {
+	r := setupRouter(failingLimiter{}, ratelimit.Limit{Requests: 1, Window: time.Minute})
+
+	assert.Equal(t, http.StatusOK, doRequest(r, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, doRequest(r, "10.0.0.1").Code)
+}
+
+func TestFailSafeLimiter_SkipsSilentlyWhileOpen(t *testing.T) {
+	log := new(mocks.MockLogger)
+	// В лог попадает только переход breaker, а не каждый запрос
+	log.On("Warn", "Circuit breaker opened", mock.Anything).Once()
+	calls := 0
+	next := limiterFunc(func() error { calls++; return errors.New("redis down") })
+	l := ratelimit.NewFailSafeLimiter(next, breaker.New("redis_ratelimit", breaker.Settings{
+		Threshold: 2,
+		CoolDown:  time.Minute,
+	}, log))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/x", ratelimit.Middleware(l, "test", ratelimit.Limit{Requests: 1, Window: time.Minute}, ratelimit.ByClientIP, log),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, http.StatusOK, doRequest(r, "10.0.0.1").Code)
+	}
+	assert.Equal(t, 2, calls, "open breaker must not reach Redis")
+	log.AssertExpectations(t)
+}
+
+type limiterFunc func() error
+
+func (f limiterFunc) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, f()
+}
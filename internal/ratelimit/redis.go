@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript — GCRA на стороне Redis. Время берётся из TIME самого Redis, чтобы
+// расхождение часов между репликами сервиса не влияло на лимит.
+//
+// KEYS[1] — ключ; ARGV[1] — интервал восстановления единицы квоты, ARGV[2] — окно (мкс).
+// Возвращает {allowed, remaining, retry_after, reset_after} (времена в мкс).
+var gcraScript = redis.NewScript(`
+local interval = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tat < now then
+  tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - window
+if now < allow_at then
+  return {0, 0, allow_at - now, tat - now}
+end
+
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / interval), 0, new_tat - now}
+`)
+
+// RedisLimiter хранит состояние в Redis, поэтому лимит общий для всех реплик.
+// Клиент общий с кэшем; закрывает и проверяет его владелец.
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+var _ Limiter = (*RedisLimiter)(nil)
+
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: "ratelimit:"}
+}
+
+func (r *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	vals, err := gcraScript.Run(ctx, r.client, []string{r.prefix + key},
+		limit.interval().Microseconds(), limit.Window.Microseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit %s: %w", key, err)
+	}
+	if len(vals) != 4 {
+		return Result{}, fmt.Errorf("rate limit %s: unexpected script reply %v", key, vals)
+	}
+
+	return Result{
+		Allowed:    vals[0] == 1,
+		Limit:      limit.Requests,
+		Remaining:  int(vals[1]),
+		RetryAfter: time.Duration(vals[2]) * time.Microsecond,
+		ResetAfter: time.Duration(vals[3]) * time.Microsecond,
+	}, nil
+}
@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrNotFound      = errors.New("record not found")
 	ErrAlreadyExists = errors.New("slug already exists")
+	// ErrUnavailable means the storage is temporarily refusing calls (e.g. an open circuit breaker).
+	ErrUnavailable = errors.New("storage unavailable")
 )
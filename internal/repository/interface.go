@@ -9,12 +9,17 @@ import (
 // URLReader defines read-only operations for URL entities.
 type URLReader interface {
 	GetBySlug(ctx context.Context, slug string) (*model.Link, error)
-	GetByOriginalURL(ctx context.Context, original string) (*model.Link, error)
+	// GetByCanonicalURL returns the newest unexpired link of ownerID whose canonical URL equals key.
+	// An empty ownerID matches only anonymous links.
+	GetByCanonicalURL(ctx context.Context, ownerID, key string) (*model.Link, error)
 }
 
 // URLWriter defines write operations for URL entities.
 type URLWriter interface {
 	Create(ctx context.Context, url *model.Link) error
+	// Update overwrites the mutable fields of the link identified by its slug.
+	Update(ctx context.Context, url *model.Link) error
+	Delete(ctx context.Context, slug string) error
 }
 
 // URLRepository combines read and write operations.
@@ -22,3 +27,49 @@ type URLRepository interface {
 	URLReader
 	URLWriter
 }
+
+// ClickWriter persists click events in batches.
+type ClickWriter interface {
+	SaveClicks(ctx context.Context, clicks []model.Click) error
+}
+
+// StatsReader provides aggregated click statistics for a link.
+type StatsReader interface {
+	// LinkStats returns totals, a sparse time series (only buckets with clicks)
+	// and top referrers/user-agent families for clicks in [q.From, q.To).
+	LinkStats(ctx context.Context, slug string, q model.StatsQuery) (*model.LinkStats, error)
+}
+
+// SlugSequence hands out unique, monotonically increasing numbers
+// for counter-based slug strategies.
+type SlugSequence interface {
+	NextSlugID(ctx context.Context) (int64, error)
+}
+
+// SlugPoolStore keeps pre-generated slugs that are not used by any link yet.
+type SlugPoolStore interface {
+	// AddPooledSlugs stores the candidates that are neither taken by a link nor
+	// already pooled and reports how many were added.
+	AddPooledSlugs(ctx context.Context, slugs []string) (int, error)
+	// TakePooledSlugs removes up to n slugs from the pool and returns them.
+	// Each pooled slug is handed out at most once, even across instances.
+	TakePooledSlugs(ctx context.Context, n int) ([]string, error)
+}
+
+// APIKeyStore keeps API keys. Only SHA-256 hashes of the keys are stored.
+type APIKeyStore interface {
+	// CreateAPIKey stores the key and sets its ID; a duplicate hash yields ErrAlreadyExists.
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	// GetAPIKeyByHash returns the key with the given hash, including revoked ones.
+	GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+}
+
+// Store is everything a storage backend provides to the application.
+type Store interface {
+	URLRepository
+	ClickWriter
+	StatsReader
+	SlugSequence
+	SlugPoolStore
+	APIKeyStore
+}
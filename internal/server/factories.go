@@ -2,29 +2,83 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/Thoustick/SlugKiller/config"
 	"github.com/Thoustick/SlugKiller/infrastructure/redis"
+	"github.com/Thoustick/SlugKiller/internal/breaker"
 	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/ratelimit"
 	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/internal/storage"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 )
 
+var errRedisClientRequired = errors.New("redis client is not configured")
+
 // Реальная фабрика для продакшена
-func ProductionStorageFactory(ctx context.Context, cfg *config.Config, log logger.Logger) (repository.URLRepository, error) {
+func ProductionStorageFactory(ctx context.Context, cfg *config.Config, log logger.Logger) (repository.Store, error) {
 	return storage.InitStorage(ctx, cfg, log)
 }
 
-func ProductionCacheProvider(cfg *config.Config, log logger.Logger) (cache.URLCache, error) {
-	redisClient, err := redis.NewRedisClient(
-		cfg.RedisHost,
-		cfg.RedisPass,
-		cfg.RedisDB,
-		log,
-	)
-	if err != nil {
-		return nil, err
+// ProductionRedisClient подключается к Redis, если он нужен кэшу или лимитам; иначе возвращает nil.
+// Один клиент (и один пул соединений) используется обоими.
+func ProductionRedisClient(cfg *config.Config, log logger.Logger) (*redis.Client, error) {
+	if cfg.CacheType != "redis" && cfg.RateLimitBackend != "redis" {
+		return nil, nil
+	}
+	return redis.NewRedisClient(cfg.RedisHost, cfg.RedisPass, cfg.RedisDB, log)
+}
+
+// ProductionCacheProvider выбирает кэш по CACHE_TYPE. Redis оборачивается circuit breaker,
+// так что его сбой во время работы превращается в промахи; при CACHE_LOCAL_SIZE > 0 перед ним ставится локальный LRU.
+// Локальный уровень требует положительного CACHE_LOCAL_TTL_SECONDS: без срока жизни изменение или удаление
+// ссылки никогда не дошло бы до других реплик.
+func ProductionCacheProvider(cfg *config.Config, redisClient *redis.Client, log logger.Logger) (cache.URLCache, error) {
+	switch cfg.CacheType {
+	case "memory":
+		return cache.NewMemoryCache(), nil
+	case "none":
+		return cache.NewNoopCache(), nil
+	case "redis":
+		if cfg.CacheLocalSize > 0 && cfg.CacheLocalTTL <= 0 {
+			return nil, fmt.Errorf("invalid local cache TTL: %s (CACHE_LOCAL_TTL_SECONDS must be positive when CACHE_LOCAL_SIZE > 0)", cfg.CacheLocalTTL)
+		}
+		if redisClient == nil {
+			return nil, errRedisClientRequired
+		}
+		cacheBreaker := breaker.New("redis", breaker.Settings{
+			Threshold: cfg.CacheBreakerThreshold,
+			CoolDown:  cfg.CacheBreakerCoolDown,
+		}, log)
+		var shared cache.URLCache = cache.NewFailSafeCache(cache.NewRedisCache(redisClient.Client()), cacheBreaker)
+		if cfg.CacheLocalSize <= 0 {
+			return shared, nil
+		}
+		return cache.NewTieredCache(cache.NewLRUCache(cfg.CacheLocalSize, cfg.CacheLocalTTL), shared), nil
+	default:
+		return nil, fmt.Errorf("invalid cache type: %s", cfg.CacheType)
+	}
+}
+
+// ProductionRateLimiter выбирает хранилище счётчиков: Redis, чтобы лимиты действовали
+// на все реплики, или память процесса для запуска без инфраструктуры. Redis ставится за
+// circuit breaker с настройками кэша: при его сбое лимиты отключаются, а не тормозят запросы.
+func ProductionRateLimiter(cfg *config.Config, redisClient *redis.Client, log logger.Logger) (ratelimit.Limiter, error) {
+	switch cfg.RateLimitBackend {
+	case "memory":
+		return ratelimit.NewMemoryLimiter(), nil
+	case "redis":
+		if redisClient == nil {
+			return nil, errRedisClientRequired
+		}
+		limiterBreaker := breaker.New("redis_ratelimit", breaker.Settings{
+			Threshold: cfg.CacheBreakerThreshold,
+			CoolDown:  cfg.CacheBreakerCoolDown,
+		}, log)
+		return ratelimit.NewFailSafeLimiter(ratelimit.NewRedisLimiter(redisClient.Client()), limiterBreaker), nil
+	default:
+		return nil, fmt.Errorf("invalid rate limit backend: %s", cfg.RateLimitBackend)
 	}
-	return cache.NewRedisCache(redisClient.Client()), nil
 }
@@ -2,54 +2,131 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/Thoustick/SlugKiller/config"
 	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/health"
 	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 )
 
 type (
-	StorageFactory func(ctx context.Context, cfg *config.Config, log logger.Logger) (repository.URLRepository, error)
+	StorageFactory func(ctx context.Context, cfg *config.Config, log logger.Logger) (repository.Store, error)
 	CacheProvider  func(cfg *config.Config, log logger.Logger) (cache.URLCache, error)
 )
 
+// Worker — фоновый процесс приложения. Run должен вернуться после отмены ctx,
+// завершив текущую работу.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// defaultShutdownTimeout — сколько ждать остановки, если SHUTDOWN_TIMEOUT_SECONDS не задан.
+const defaultShutdownTimeout = 10 * time.Second
+
 type App struct {
-	Engine *gin.Engine
-	Cfg    *config.Config
-	Ctx    context.Context
-	Cancel context.CancelFunc
-	Logger logger.Logger
+	Engine  *gin.Engine
+	Cfg     *config.Config
+	Ctx     context.Context
+	Cancel  context.CancelFunc
+	Logger  logger.Logger
+	Workers []Worker
+	// Admin — служебные маршруты (метрики) для отдельного адреса Cfg.MetricsAddr;
+	// nil, если они обслуживаются основным сервером.
+	Admin http.Handler
+	// Closers вызываются по порядку после остановки воркеров: закрытие пулов соединений, сброс спанов трассировки.
+	Closers []func(context.Context) error
+	// Health — состояние для /readyz: до запуска и во время остановки приложение не готово.
+	Health *health.Health
 }
 
+// Run запускает HTTP-серверы и воркеры и блокируется до отмены Ctx. Остановка идёт по порядку:
+// снятие готовности, HTTP-серверы, воркеры, затем Closers. Все шаги укладываются в Cfg.ShutdownTimeout;
+// ошибки шагов объединяются в возвращаемую ошибку.
 func (a *App) Run() error {
-	srv := &http.Server{
+	servers := []*http.Server{{
 		Addr:    a.Cfg.HTTPAddr,
 		Handler: a.Engine,
-	}
-
-	go func() {
-		a.Logger.Info("starting HTTP server", map[string]interface{}{
-			"addr": a.Cfg.HTTPAddr,
+	}}
+	if a.Admin != nil {
+		servers = append(servers, &http.Server{
+			Addr:    a.Cfg.MetricsAddr,
+			Handler: a.Admin,
 		})
+	}
 
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			a.Logger.Error("HTTP server error", err, nil)
+	// Сначала занимаем порты: ошибка привязки возвращается сразу, а готовность
+	// объявляется только когда сервер действительно принимает соединения
+	listeners := make([]net.Listener, 0, len(servers))
+	for _, s := range servers {
+		ln, err := net.Listen("tcp", s.Addr)
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			a.Logger.Error("failed to listen", err, map[string]interface{}{
+				"addr": s.Addr,
+			})
+			ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
+			defer cancel()
+			return errors.Join(err, a.closeResources(ctx))
 		}
-	}()
+		listeners = append(listeners, ln)
+	}
+
+	// Воркеры живут дольше HTTP-сервера, чтобы успеть обработать события последних запросов
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, w := range a.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Run(workersCtx)
+		}()
+	}
+
+	for i, s := range servers {
+		go a.serve(s, listeners[i])
+	}
+	a.setHealthState(health.StateReady)
 
 	<-a.Ctx.Done()
 
 	a.Logger.Info("shutdown signal received", nil)
 
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Снимаем готовность до остановки сервера, чтобы балансировщик успел убрать инстанс
+	a.setHealthState(health.StateShuttingDown)
+	if a.Cfg.ReadinessDrainDelay > 0 {
+		time.Sleep(a.Cfg.ReadinessDrainDelay)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), a.shutdownTimeout())
 	defer shutdownCancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
+	var errs []error
+	for _, s := range servers {
+		if err := s.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown HTTP server %s: %w", s.Addr, err))
+		}
+	}
+
+	stopWorkers()
+	if err := waitContext(shutdownCtx, &wg); err != nil {
+		errs = append(errs, fmt.Errorf("stop workers: %w", err))
+	}
+
+	errs = append(errs, a.closeResources(shutdownCtx))
+	err := errors.Join(errs...)
+
+	if err != nil {
 		a.Logger.Error("graceful shutdown failed", err, nil)
 		return err
 	}
@@ -57,3 +134,52 @@ func (a *App) Run() error {
 	a.Logger.Info("server stopped gracefully", nil)
 	return nil
 }
+
+// closeResources вызывает Closers по порядку; сбой одного не мешает закрыть остальные.
+func (a *App) closeResources(ctx context.Context) error {
+	var errs []error
+	for _, closeFn := range a.Closers {
+		errs = append(errs, closeFn(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (a *App) shutdownTimeout() time.Duration {
+	if a.Cfg.ShutdownTimeout > 0 {
+		return a.Cfg.ShutdownTimeout
+	}
+	return defaultShutdownTimeout
+}
+
+// waitContext ждёт wg, но не дольше, чем живёт ctx.
+func waitContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *App) serve(srv *http.Server, ln net.Listener) {
+	a.Logger.Info("starting HTTP server", map[string]interface{}{
+		"addr": srv.Addr,
+	})
+
+	if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		a.Logger.Error("HTTP server error", err, map[string]interface{}{
+			"addr": srv.Addr,
+		})
+	}
+}
+
+func (a *App) setHealthState(state string) {
+	if a.Health != nil {
+		a.Health.SetState(state)
+	}
+}
@@ -4,16 +4,25 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"net"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Thoustick/SlugKiller/config"
 	"github.com/Thoustick/SlugKiller/internal/cache"
 	"github.com/Thoustick/SlugKiller/internal/di"
+	"github.com/Thoustick/SlugKiller/internal/health"
+	"github.com/Thoustick/SlugKiller/internal/model"
 	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/server"
+	"github.com/Thoustick/SlugKiller/internal/service"
 	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 )
@@ -22,15 +31,16 @@ func TestNewAppWithMocks(t *testing.T) {
 	t.Run("успешная инициализация", func(t *testing.T) {
 		ctx := context.Background()
 		cfg := &config.Config{
-			HTTPAddr:    ":8080",
-			StorageType: "memory",
-			SlugLength:  8,
-			MaxAttempts: 5,
-			CacheTTL:    300,
+			HTTPAddr:            ":8080",
+			StorageType:         "memory",
+			RedirectDefaultType: model.RedirectFound,
+			SlugLength:          8,
+			MaxAttempts:         5,
+			CacheTTL:            300,
 		}
 
-		mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.URLRepository, error) {
-			return &mocks.MockURLRepository{}, nil
+		mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.Store, error) {
+			return &mocks.MockStore{}, nil
 		}
 		mockCache := func(_ *config.Config, _ logger.Logger) (cache.URLCache, error) {
 			return &mocks.MockCache{}, nil
@@ -45,15 +55,16 @@ func TestNewAppWithMocks(t *testing.T) {
 	t.Run("ошибка при инициализации cache", func(t *testing.T) {
 		ctx := context.Background()
 		cfg := &config.Config{
-			HTTPAddr:    ":8080",
-			StorageType: "memory",
-			SlugLength:  8,
-			MaxAttempts: 5,
-			CacheTTL:    300,
+			HTTPAddr:            ":8080",
+			StorageType:         "memory",
+			RedirectDefaultType: model.RedirectFound,
+			SlugLength:          8,
+			MaxAttempts:         5,
+			CacheTTL:            300,
 		}
 
-		mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.URLRepository, error) {
-			return &mocks.MockURLRepository{}, nil
+		mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.Store, error) {
+			return &mocks.MockStore{}, nil
 		}
 		mockCache := func(_ *config.Config, _ logger.Logger) (cache.URLCache, error) {
 			return nil, errors.New("cache init fail")
@@ -67,14 +78,15 @@ func TestNewAppWithMocks(t *testing.T) {
 	t.Run("ошибка при инициализации storage", func(t *testing.T) {
 		ctx := context.Background()
 		cfg := &config.Config{
-			HTTPAddr:    ":8080",
-			StorageType: "memory",
-			SlugLength:  8,
-			MaxAttempts: 5,
-			CacheTTL:    300,
+			HTTPAddr:            ":8080",
+			StorageType:         "memory",
+			RedirectDefaultType: model.RedirectFound,
+			SlugLength:          8,
+			MaxAttempts:         5,
+			CacheTTL:            300,
 		}
 
-		mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.URLRepository, error) {
+		mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.Store, error) {
 			return nil, errors.New("db down")
 		}
 		mockCache := func(_ *config.Config, _ logger.Logger) (cache.URLCache, error) {
@@ -87,18 +99,39 @@ func TestNewAppWithMocks(t *testing.T) {
 	})
 }
 
+func TestNewTestApp_ValidatesConfigLikeNewApp(t *testing.T) {
+	cfg := &config.Config{HTTPAddr: ":8080", StorageType: "memory", SlugLength: 8, MaxAttempts: 5}
+
+	storageCalled := false
+	mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.Store, error) {
+		storageCalled = true
+		return &mocks.MockStore{}, nil
+	}
+	mockCache := func(_ *config.Config, _ logger.Logger) (cache.URLCache, error) {
+		return &mocks.MockCache{}, nil
+	}
+
+	// Без REDIRECT_DEFAULT_TYPE приложение не собирается, как и боевое
+	app, err := di.NewTestApp(context.Background(), cfg, mockStorage, mockCache)
+	assert.Nil(t, app)
+	assert.ErrorIs(t, err, service.ErrInvalidRedirectType)
+	assert.False(t, storageCalled)
+}
+
 func TestApp_Run(t *testing.T) {
 	ctx := context.Background()
 	cfg := &config.Config{
-		HTTPAddr:    ":8081",
-		StorageType: "memory",
-		SlugLength:  8,
-		MaxAttempts: 5,
-		CacheTTL:    60,
+		HTTPAddr:              ":8081",
+		StorageType:           "memory",
+		RedirectDefaultType:   model.RedirectFound,
+		SlugLength:            8,
+		MaxAttempts:           5,
+		CacheTTL:              60,
+		AllowAnonymousShorten: true,
 	}
 
-	mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.URLRepository, error) {
-		return &mocks.MockURLRepository{}, nil
+	mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.Store, error) {
+		return &mocks.MockStore{}, nil
 	}
 
 	mockCache := func(_ *config.Config, _ logger.Logger) (cache.URLCache, error) {
@@ -122,3 +155,184 @@ func TestApp_Run(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
+
+func TestApp_RunReadiness(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := &config.Config{
+		HTTPAddr:            "127.0.0.1:8082",
+		StorageType:         "memory",
+		RedirectDefaultType: model.RedirectFound,
+		SlugLength:          8,
+		MaxAttempts:         5,
+		ReadinessDrainDelay: time.Second,
+	}
+
+	mockStorage := func(_ context.Context, _ *config.Config, _ logger.Logger) (repository.Store, error) {
+		return &mocks.MockStore{}, nil
+	}
+	mockCache := func(_ *config.Config, _ logger.Logger) (cache.URLCache, error) {
+		return &mocks.MockCache{}, nil
+	}
+
+	app, err := di.NewTestApp(ctx, cfg, mockStorage, mockCache)
+	require.NoError(t, err)
+	assert.Equal(t, health.StateStarting, app.Health.State())
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://127.0.0.1:8082/readyz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	// Во время паузы перед остановкой сервер ещё отвечает, но уже не готов
+	cancel()
+	require.Eventually(t, func() bool {
+		return app.Health.State() == health.StateShuttingDown
+	}, time.Second, 10*time.Millisecond)
+	resp, err := http.Get("http://127.0.0.1:8082/readyz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, err = http.Get("http://127.0.0.1:8082/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.NoError(t, <-done)
+}
+
+func TestApp_RunListenError(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer busy.Close()
+
+	cfg := &config.Config{HTTPAddr: busy.Addr().String()}
+	log := &mocks.MockLogger{}
+	log.On("Error", "failed to listen", mock.Anything, mock.Anything).Return()
+
+	var closed bool
+	app := &server.App{
+		Cfg:     cfg,
+		Ctx:     context.Background(),
+		Logger:  log,
+		Health:  health.New(0, log),
+		Closers: []func(context.Context) error{func(context.Context) error { closed = true; return nil }},
+	}
+	assert.Error(t, app.Run())
+	assert.Equal(t, health.StateStarting, app.Health.State())
+	assert.True(t, closed, "resources must be released when the server cannot start")
+}
+
+// recordingWorker отмечает в журнале момент своей остановки.
+type recordingWorker struct {
+	steps *[]string
+	mu    *sync.Mutex
+}
+
+func (w recordingWorker) Run(ctx context.Context) {
+	<-ctx.Done()
+	w.mu.Lock()
+	*w.steps = append(*w.steps, "worker")
+	w.mu.Unlock()
+}
+
+func TestApp_RunClosesInOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var (
+		mu    sync.Mutex
+		steps []string
+	)
+	closer := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			steps = append(steps, name)
+			mu.Unlock()
+			return err
+		}
+	}
+
+	log := &mocks.MockLogger{}
+	log.On("Info", mock.Anything, mock.Anything).Return()
+	log.On("Error", "graceful shutdown failed", mock.Anything, mock.Anything).Return()
+
+	app := &server.App{
+		Engine:  gin.New(),
+		Cfg:     &config.Config{HTTPAddr: "127.0.0.1:0", ShutdownTimeout: time.Second},
+		Ctx:     ctx,
+		Logger:  log,
+		Workers: []server.Worker{recordingWorker{steps: &steps, mu: &mu}},
+		Closers: []func(context.Context) error{
+			closer("postgres", errors.New("pool busy")),
+			closer("redis", nil),
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	cancel()
+
+	err := <-done
+	assert.EqualError(t, err, "pool busy")
+	// Сбой закрытия базы не мешает закрыть Redis
+	assert.Equal(t, []string{"worker", "postgres", "redis"}, steps)
+}
+
+// stuckWorker не реагирует на отмену контекста.
+type stuckWorker struct{ release chan struct{} }
+
+func (w stuckWorker) Run(context.Context) { <-w.release }
+
+func TestApp_RunShutdownTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	log := &mocks.MockLogger{}
+	log.On("Info", mock.Anything, mock.Anything).Return()
+	log.On("Error", "graceful shutdown failed", mock.Anything, mock.Anything).Return()
+
+	worker := stuckWorker{release: make(chan struct{})}
+	defer close(worker.release)
+
+	app := &server.App{
+		Engine:  gin.New(),
+		Cfg:     &config.Config{HTTPAddr: "127.0.0.1:0", ShutdownTimeout: 50 * time.Millisecond},
+		Ctx:     ctx,
+		Logger:  log,
+		Workers: []server.Worker{worker},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not respect the shutdown timeout")
+	}
+}
+
+func TestProductionCacheProvider_WithoutRedis(t *testing.T) {
+	log := &mocks.MockLogger{}
+
+	c, err := server.ProductionCacheProvider(&config.Config{CacheType: "memory"}, nil, log)
+	assert.NoError(t, err)
+	assert.IsType(t, &cache.MemoryCache{}, c)
+
+	c, err = server.ProductionCacheProvider(&config.Config{CacheType: "none"}, nil, log)
+	assert.NoError(t, err)
+	assert.IsType(t, cache.NoopCache{}, c)
+
+	_, err = server.ProductionCacheProvider(&config.Config{CacheType: "memcached"}, nil, log)
+	assert.EqualError(t, err, "invalid cache type: memcached")
+
+	_, err = server.ProductionCacheProvider(&config.Config{CacheType: "redis", CacheLocalSize: 100}, nil, log)
+	assert.ErrorContains(t, err, "invalid local cache TTL")
+}
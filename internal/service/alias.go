@@ -0,0 +1,53 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtinReservedAliases — пути, которые сервис использует сам или может использовать в будущем.
+var builtinReservedAliases = []string{
+	"shorten", "api", "health", "healthz", "readyz", "metrics", "admin", "static",
+}
+
+// validateAlias проверяет пользовательский алиас: длину, набор символов и список зарезервированных слов.
+func (s *urlService) validateAlias(alias string) error {
+	if len(alias) < s.cfg.AliasMinLength || len(alias) > s.cfg.AliasMaxLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters",
+			ErrInvalidAlias, s.cfg.AliasMinLength, s.cfg.AliasMaxLength)
+	}
+
+	for _, r := range alias {
+		if !isAliasRune(r) {
+			return fmt.Errorf("%w: only letters, digits, '_' and '-' are allowed", ErrInvalidAlias)
+		}
+	}
+
+	if s.isReservedSlug(alias) {
+		return ErrAliasReserved
+	}
+	return nil
+}
+
+// isReservedSlug сообщает, совпадает ли slug (без учёта регистра) с зарезервированным словом.
+func (s *urlService) isReservedSlug(slug string) bool {
+	lower := strings.ToLower(slug)
+	for _, reserved := range builtinReservedAliases {
+		if lower == reserved {
+			return true
+		}
+	}
+	for _, reserved := range s.cfg.ReservedAliases {
+		if lower == strings.ToLower(reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAliasRune(r rune) bool {
+	return r >= 'a' && r <= 'z' ||
+		r >= 'A' && r <= 'Z' ||
+		r >= '0' && r <= '9' ||
+		r == '_' || r == '-'
+}
@@ -0,0 +1,16 @@
+package service
+
+import "github.com/Thoustick/SlugKiller/pkg/urlnorm"
+
+// canonicalURL возвращает ключ для поиска дублей. Адрес, который не удаётся
+// нормализовать, используется как есть — тогда совпадут только точные копии.
+func (s *urlService) canonicalURL(rawURL string) string {
+	canonical, err := urlnorm.Normalize(rawURL, urlnorm.Options{
+		StripFragment:  s.cfg.URLStripFragment,
+		TrackingParams: s.cfg.URLTrackingParams,
+	})
+	if err != nil {
+		return rawURL
+	}
+	return canonical
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrLinkExpired   = errors.New("link expired")
+	ErrLinkDisabled  = errors.New("link disabled")
+	ErrEmptyURL      = errors.New("empty URL provided")
+	ErrInvalidStatus = errors.New("invalid link status")
+	ErrInvalidExpiry = errors.New("expiration time must be in the future")
+	ErrInvalidAlias  = errors.New("invalid alias")
+	ErrAliasReserved = errors.New("alias is reserved")
+
+	ErrInvalidRedirectType = errors.New("redirect type must be one of 301, 302, 307, 308")
+	ErrInvalidPassthrough  = errors.New("query passthrough must be one of keep, override, append")
+	ErrUnknownUTMTemplate  = errors.New("unknown UTM template")
+	ErrInvalidRule         = errors.New("invalid redirect rule")
+	ErrInvalidTargets      = errors.New("invalid split targets")
+
+	ErrInvalidStatsQuery = errors.New("invalid stats query")
+)
+
+// IsRejected сообщает, что запрос отклонён из-за данных клиента: неверные параметры ссылки
+// или адрес, нарушающий политику. Такие ошибки — не сбой сервиса; по ним отвечают 4xx.
+func IsRejected(err error) bool {
+	var violation *URLPolicyError
+	switch {
+	case errors.As(err, &violation),
+		errors.Is(err, ErrEmptyURL),
+		errors.Is(err, ErrInvalidStatus),
+		errors.Is(err, ErrInvalidExpiry),
+		errors.Is(err, ErrInvalidAlias),
+		errors.Is(err, ErrAliasReserved),
+		errors.Is(err, ErrInvalidRedirectType),
+		errors.Is(err, ErrInvalidPassthrough),
+		errors.Is(err, ErrUnknownUTMTemplate),
+		errors.Is(err, ErrInvalidRule),
+		errors.Is(err, ErrInvalidTargets),
+		errors.Is(err, ErrInvalidStatsQuery):
+		return true
+	}
+	return false
+}
+
+// Коды причин, по которым политика отклоняет адрес назначения
+const (
+	URLPolicyInvalidURL       = "invalid_url"
+	URLPolicySchemeNotAllowed = "scheme_not_allowed"
+	URLPolicyIPLiteral        = "ip_literal"
+	URLPolicyPrivateAddress   = "private_address"
+	URLPolicyOwnDomain        = "own_domain"
+	URLPolicyDomainBlocked    = "domain_blocked"
+)
+
+// URLPolicyError — адрес назначения нарушает политику безопасности.
+type URLPolicyError struct {
+	Reason string // один из кодов URLPolicy*
+	Detail string
+}
+
+func (e *URLPolicyError) Error() string {
+	return fmt.Sprintf("url rejected (%s): %s", e.Reason, e.Detail)
+}
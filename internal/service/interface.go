@@ -1,12 +1,102 @@
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
 
 type URLService interface {
-	Shorten(ctx context.Context, originalURL string) (string, error)
-	Resolve(ctx context.Context, shortURL string) (string, error)
+	Shorten(ctx context.Context, originalURL string, opts ShortenOptions) (string, error)
+	Resolve(ctx context.Context, shortURL string, v Visitor) (*Redirect, error)
+
+	// Методы управления работают только со ссылками владельца ownerID; чужая ссылка
+	// неотличима от несуществующей (repository.ErrNotFound). Владельцам из ADMIN_OWNERS доступны все ссылки.
+	GetLink(ctx context.Context, ownerID, slug string) (*model.Link, error)
+	UpdateLink(ctx context.Context, ownerID, slug string, upd LinkUpdate) (*model.Link, error)
+	DeleteLink(ctx context.Context, ownerID, slug string) error
+}
+
+// StatsService отдаёт статистику переходов по ссылкам владельца ownerID.
+type StatsService interface {
+	LinkStats(ctx context.Context, ownerID, slug string, q model.StatsQuery) (*model.LinkStats, error)
 }
 
 type SlugGenerator interface {
 	Generate(ctx context.Context) (string, error)
 }
+
+// CollisionFreeSlugGenerator — генератор, который по построению не выдаёт один slug дважды.
+// Для таких генераторов сервис не проверяет занятость slug перед созданием ссылки.
+type CollisionFreeSlugGenerator interface {
+	SlugGenerator
+	CollisionFree() bool
+}
+
+// URLPolicy решает, можно ли сокращать адрес. Нарушения возвращаются как *URLPolicyError.
+type URLPolicy interface {
+	Check(ctx context.Context, rawURL string) error
+}
+
+// ShortenOptions — необязательные параметры создаваемой ссылки.
+type ShortenOptions struct {
+	ExpiresAt *time.Time // момент, после которого ссылка перестаёт работать
+	Alias     string     // пользовательский slug; если задан, генератор не используется
+	OwnerID   string     // владелец ссылки; пусто — анонимная ссылка. Дубли ищутся только среди ссылок владельца
+
+	RedirectType int // статус перенаправления (301, 302, 307, 308); 0 — тип по умолчанию из конфигурации
+
+	QueryPassthrough string // режим передачи параметров запроса посетителя (model.QueryPassthrough*)
+	PathPassthrough  bool   // дописывать хвост пути после slug к адресу назначения
+
+	UTM         *model.UTM // UTM-метки, дописываемые при переходе
+	UTMTemplate string     // имя шаблона UTM из конфигурации
+
+	Rules []model.LinkRule // правила перехода по устройству, проверяются по порядку
+
+	Targets       []model.LinkTarget // варианты адреса назначения с весами для A/B-разбиения
+	StickyTargets bool               // закреплять вариант за посетителем
+}
+
+// LinkUpdate — изменяемые поля ссылки; nil означает «оставить как есть».
+type LinkUpdate struct {
+	URL          *string
+	Status       *string
+	RedirectType *int // 0 — вернуть тип по умолчанию из конфигурации
+
+	QueryPassthrough *string // пустая строка выключает передачу параметров
+	PathPassthrough  *bool
+
+	UTM         *model.UTM // заменяет метки ссылки целиком; пустой набор удаляет их
+	UTMTemplate *string    // пустая строка отвязывает шаблон
+
+	Rules *[]model.LinkRule // заменяет правила целиком; пустой список удаляет их
+
+	Targets       *[]model.LinkTarget // заменяет варианты целиком; пустой список выключает разбиение
+	StickyTargets *bool
+}
+
+// Visitor — сведения о посетителе, от которых зависит адрес перехода.
+type Visitor struct {
+	UserAgent string
+	Variant   string // вариант A/B-разбиения, закреплённый за посетителем ранее; пусто — не закреплён
+}
+
+// Redirect — ответ на переход по короткой ссылке.
+type Redirect struct {
+	URL    string
+	Status int           // HTTP-статус перенаправления
+	MaxAge time.Duration // сколько браузеру можно помнить ответ; 0 — не кэшировать
+
+	QueryPassthrough string // см. Location
+	PathPassthrough  bool
+	UTM              model.UTM // метки шаблона и ссылки вместе
+
+	// VaryUserAgent — адрес выбран правилом по устройству, и общие кэши должны различать
+	// ответы по User-Agent
+	VaryUserAgent bool
+
+	Variant       string        // выбранный вариант A/B-разбиения; пусто — разбиения нет
+	VariantMaxAge time.Duration // сколько помнить вариант за посетителем; 0 — не закреплять
+}
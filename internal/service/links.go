@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// GetLink возвращает метаданные ссылки владельца по slug.
+func (s *urlService) GetLink(ctx context.Context, ownerID, slug string) (*model.Link, error) {
+	link, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("Failed to fetch link", err, map[string]interface{}{"slug": slug})
+		}
+		return nil, err
+	}
+	if !s.admins.canManage(link, ownerID) {
+		s.logger.Warn("Access to another owner's link denied", map[string]interface{}{
+			"slug":     slug,
+			"owner_id": ownerID,
+		})
+		return nil, repository.ErrNotFound
+	}
+	return link, nil
+}
+
+// UpdateLink меняет адрес назначения, статус и настройки перехода ссылки и сбрасывает её из кэша.
+func (s *urlService) UpdateLink(ctx context.Context, ownerID, slug string, upd LinkUpdate) (*model.Link, error) {
+	if upd.URL != nil && *upd.URL == "" {
+		return nil, ErrEmptyURL
+	}
+	if upd.Status != nil && *upd.Status != model.LinkStatusActive && *upd.Status != model.LinkStatusDisabled {
+		return nil, ErrInvalidStatus
+	}
+	if upd.RedirectType != nil {
+		if err := validateRedirectType(*upd.RedirectType); err != nil {
+			return nil, err
+		}
+	}
+	if upd.QueryPassthrough != nil {
+		if err := validatePassthrough(*upd.QueryPassthrough); err != nil {
+			return nil, err
+		}
+	}
+	if upd.UTMTemplate != nil {
+		if err := s.validateUTMTemplate(*upd.UTMTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if upd.URL != nil {
+		if err := s.checkPolicy(ctx, *upd.URL); err != nil {
+			return nil, err
+		}
+	}
+	if upd.Rules != nil {
+		if err := s.checkRules(ctx, *upd.Rules); err != nil {
+			return nil, err
+		}
+	}
+	if upd.Targets != nil {
+		if err := s.checkTargets(ctx, *upd.Targets); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.GetLink(ctx, ownerID, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	// Работаем с копией: хранилище может отдавать разделяемый объект
+	link := *current
+	if upd.URL != nil {
+		link.URL = *upd.URL
+		link.CanonicalURL = s.canonicalURL(*upd.URL)
+	}
+	if upd.Status != nil {
+		link.Status = *upd.Status
+	}
+	if upd.RedirectType != nil {
+		link.RedirectType = *upd.RedirectType
+	}
+	if upd.QueryPassthrough != nil {
+		link.QueryPassthrough = *upd.QueryPassthrough
+	}
+	if upd.PathPassthrough != nil {
+		link.PathPassthrough = *upd.PathPassthrough
+	}
+	if upd.UTM != nil {
+		link.UTM = normalizeUTM(upd.UTM)
+	}
+	if upd.UTMTemplate != nil {
+		link.UTMTemplate = *upd.UTMTemplate
+	}
+	if upd.Rules != nil {
+		link.Rules = normalizeRules(*upd.Rules)
+	}
+	if upd.Targets != nil {
+		link.Targets = normalizeTargets(*upd.Targets)
+	}
+	if upd.StickyTargets != nil {
+		link.StickyTargets = *upd.StickyTargets
+	}
+
+	if err := s.repo.Update(ctx, &link); err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("Failed to update link", err, map[string]interface{}{"slug": slug})
+		}
+		return nil, err
+	}
+	s.invalidateCache(ctx, slug)
+
+	s.logger.Info("Link updated", map[string]interface{}{
+		"slug":   slug,
+		"url":    link.URL,
+		"status": link.Status,
+	})
+	return &link, nil
+}
+
+// DeleteLink удаляет ссылку владельца и сбрасывает её из кэша.
+func (s *urlService) DeleteLink(ctx context.Context, ownerID, slug string) error {
+	if _, err := s.GetLink(ctx, ownerID, slug); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, slug); err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			s.logger.Error("Failed to delete link", err, map[string]interface{}{"slug": slug})
+		}
+		return err
+	}
+	s.invalidateCache(ctx, slug)
+
+	s.logger.Info("Link deleted", map[string]interface{}{"slug": slug})
+	return nil
+}
+
+// invalidateCache удаляет slug из кэша. Ошибка не фатальна: кэш перед Redis повторит удаление,
+// когда Redis снова станет доступен.
+func (s *urlService) invalidateCache(ctx context.Context, slug string) {
+	if err := s.cache.Delete(ctx, slug); err != nil {
+		s.logger.Warn("Failed to invalidate cache", map[string]interface{}{
+			"slug":  slug,
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestUpdateLink_InvalidatesCache(t *testing.T) {
+	ts := setupURLService()
+	stored := &model.Link{Slug: "abc", URL: "https://old.com", Status: model.LinkStatusActive}
+	newURL := "https://new.com"
+
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(stored, nil).Once()
+	ts.repo.On("Update", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Slug == "abc" && l.URL == newURL && l.Status == model.LinkStatusActive
+	})).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "abc").Return(nil).Once()
+
+	link, err := ts.svc.UpdateLink(context.Background(), "", "abc", service.LinkUpdate{URL: &newURL})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newURL, link.URL)
+	assert.Equal(t, "https://old.com", stored.URL, "stored link must not be mutated in place")
+	ts.repo.AssertExpectations(t)
+	ts.cache.AssertExpectations(t)
+}
+
+func TestUpdateLink_InvalidStatus(t *testing.T) {
+	ts := setupURLService()
+	status := "paused"
+
+	_, err := ts.svc.UpdateLink(context.Background(), "", "abc", service.LinkUpdate{Status: &status})
+
+	assert.ErrorIs(t, err, service.ErrInvalidStatus)
+	ts.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateLink_NotFound(t *testing.T) {
+	ts := setupURLService()
+	status := model.LinkStatusDisabled
+
+	ts.repo.On("GetBySlug", mock.Anything, "missing").Return(nil, repository.ErrNotFound).Once()
+
+	_, err := ts.svc.UpdateLink(context.Background(), "", "missing", service.LinkUpdate{Status: &status})
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	ts.cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDeleteLink_InvalidatesCache(t *testing.T) {
+	ts := setupURLService()
+
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc"}, nil).Once()
+	ts.repo.On("Delete", mock.Anything, "abc").Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "abc").Return(errors.New("redis down")).Once()
+
+	// Ошибка кэша не должна ломать удаление
+	err := ts.svc.DeleteLink(context.Background(), "", "abc")
+
+	assert.NoError(t, err)
+	ts.repo.AssertExpectations(t)
+	ts.cache.AssertExpectations(t)
+}
+
+func TestLinkManagement_OtherOwner(t *testing.T) {
+	ts := setupURLService()
+	status := model.LinkStatusDisabled
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc", OwnerID: "team-a"}, nil)
+
+	// Чужая ссылка выглядит как несуществующая
+	_, err := ts.svc.GetLink(context.Background(), "team-b", "abc")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = ts.svc.UpdateLink(context.Background(), "team-b", "abc", service.LinkUpdate{Status: &status})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	err = ts.svc.DeleteLink(context.Background(), "team-b", "abc")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	ts.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	ts.repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	ts.cache.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+
+	link, err := ts.svc.GetLink(context.Background(), "team-a", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "team-a", link.OwnerID)
+}
+
+func TestLinkManagement_AdminManagesAnonymousLinks(t *testing.T) {
+	ts := setupURLServiceWithConfig(func(cfg *config.Config) { cfg.AdminOwners = []string{"ops"} })
+	status := model.LinkStatusDisabled
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc"}, nil)
+	ts.repo.On("Update", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.OwnerID == "" && l.Status == model.LinkStatusDisabled
+	})).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "abc").Return(nil).Once()
+
+	// Анонимная ссылка недоступна обычному владельцу, но доступна администратору
+	_, err := ts.svc.GetLink(context.Background(), "team-a", "abc")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	link, err := ts.svc.UpdateLink(context.Background(), "ops", "abc", service.LinkUpdate{Status: &status})
+	assert.NoError(t, err)
+	assert.Empty(t, link.OwnerID, "administration must not change the owner")
+	ts.repo.AssertExpectations(t)
+}
+
+func TestResolve_DisabledLink(t *testing.T) {
+	svc, repo, cache, _ := setupResolveService()
+
+	cache.On("Get", mock.Anything, "off").Return("", errors.New("cache miss"))
+	repo.On("GetBySlug", mock.Anything, "off").Return(&model.Link{
+		Slug:   "off",
+		URL:    "https://example.com",
+		Status: model.LinkStatusDisabled,
+	}, nil)
+
+	r, err := svc.Resolve(context.Background(), "off", service.Visitor{})
+
+	assert.ErrorIs(t, err, service.ErrLinkDisabled)
+	assert.Nil(t, r)
+}
+
+func TestUpdateLink_RejectedByPolicy(t *testing.T) {
+	ts := setupURLService()
+	policy := new(mocks.MockURLPolicy)
+	svc := service.NewURLService(ts.repo, ts.logger, ts.cache, &config.Config{}, ts.slugGen, policy)
+	newURL := "file:///etc/passwd"
+
+	policy.On("Check", mock.Anything, newURL).
+		Return(&service.URLPolicyError{Reason: service.URLPolicySchemeNotAllowed}).Once()
+
+	_, err := svc.UpdateLink(context.Background(), "", "abc", service.LinkUpdate{URL: &newURL})
+
+	var violation *service.URLPolicyError
+	assert.ErrorAs(t, err, &violation)
+	ts.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
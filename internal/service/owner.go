@@ -0,0 +1,26 @@
+package service
+
+import "github.com/Thoustick/SlugKiller/internal/model"
+
+// admins — владельцы из ADMIN_OWNERS. Им доступны все ссылки, в том числе анонимные
+// и созданные до появления API-ключей: у таких ссылок нет владельца, который мог бы ими управлять.
+type admins map[string]struct{}
+
+func newAdmins(ownerIDs []string) admins {
+	a := make(admins, len(ownerIDs))
+	for _, id := range ownerIDs {
+		if id != "" {
+			a[id] = struct{}{}
+		}
+	}
+	return a
+}
+
+// canManage сообщает, может ли ownerID управлять ссылкой и читать её статистику.
+func (a admins) canManage(link *model.Link, ownerID string) bool {
+	if link.OwnerID == ownerID {
+		return true
+	}
+	_, ok := a[ownerID]
+	return ok
+}
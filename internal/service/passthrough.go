@@ -0,0 +1,107 @@
+package service
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
+
+// Location возвращает адрес перехода: к адресу назначения дописываются UTM-метки ссылки,
+// а также хвост пути после slug и параметры запроса посетителя, если ссылка это разрешает.
+// suffix — путь после slug вместе с ведущим «/», query — параметры запроса посетителя.
+func (r *Redirect) Location(suffix string, query url.Values) string {
+	passPath := r.PathPassthrough && strings.Trim(suffix, "/") != ""
+	passQuery := r.QueryPassthrough != model.QueryPassthroughOff && len(query) > 0
+	if !passPath && !passQuery && r.UTM.IsZero() {
+		return r.URL
+	}
+
+	dest, err := url.Parse(r.URL)
+	if err != nil {
+		// Адрес назначения проверен при создании ссылки; если он всё же не разбирается, ведём как есть
+		return r.URL
+	}
+	if passPath {
+		dest = dest.JoinPath(pathSegments(suffix)...)
+	}
+	if !r.UTM.IsZero() {
+		// Метки ссылки главнее одноимённых параметров, оставшихся в адресе назначения
+		dest.RawQuery = mergeQuery(dest.RawQuery, r.UTM.Params(), model.QueryPassthroughOverride)
+	}
+	if passQuery {
+		dest.RawQuery = mergeQuery(dest.RawQuery, queryParams(query), r.QueryPassthrough)
+	}
+	return dest.String()
+}
+
+// pathSegments разбивает хвост пути на экранированные сегменты. «..» схлопывается относительно
+// корня хвоста, поэтому хвост не выходит за пределы пути адреса назначения.
+func pathSegments(suffix string) []string {
+	segments := strings.Split(strings.TrimPrefix(path.Clean("/"+suffix), "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return segments
+}
+
+// mergeQuery добавляет пары параметр–значение к строке запроса адреса назначения. Порядок и
+// кодирование параметров адреса назначения сохраняются: некоторые адреса подписаны целиком.
+func mergeQuery(rawDest string, incoming [][2]string, mode string) string {
+	incomingKeys := make(map[string]bool, len(incoming))
+	for _, p := range incoming {
+		incomingKeys[p[0]] = true
+	}
+
+	var parts []string
+	present := make(map[string]bool)
+	for _, part := range strings.Split(rawDest, "&") {
+		if part == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(part, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if mode == model.QueryPassthroughOverride && incomingKeys[key] {
+			continue
+		}
+		present[key] = true
+		parts = append(parts, part)
+	}
+
+	for _, p := range incoming {
+		if mode == model.QueryPassthroughKeep && present[p[0]] {
+			continue
+		}
+		parts = append(parts, url.QueryEscape(p[0])+"="+url.QueryEscape(p[1]))
+	}
+	return strings.Join(parts, "&")
+}
+
+// queryParams раскладывает параметры запроса в пары, отсортированные по имени.
+func queryParams(query url.Values) [][2]string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var params [][2]string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			params = append(params, [2]string{key, value})
+		}
+	}
+	return params
+}
+
+// validatePassthrough проверяет режим передачи параметров запроса из запроса клиента.
+func validatePassthrough(mode string) error {
+	if !model.IsValidQueryPassthrough(mode) {
+		return ErrInvalidPassthrough
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+func TestRedirect_Location(t *testing.T) {
+	tests := []struct {
+		name     string
+		redirect service.Redirect
+		suffix   string
+		query    string
+		want     string
+	}{
+		{
+			name:     "передача выключена",
+			redirect: service.Redirect{URL: "https://example.com/a?x=1"},
+			suffix:   "/extra",
+			query:    "ref=news",
+			want:     "https://example.com/a?x=1",
+		},
+		{
+			name:     "keep: значение адреса назначения не меняется",
+			redirect: service.Redirect{URL: "https://example.com/a?ref=site&x=1", QueryPassthrough: model.QueryPassthroughKeep},
+			query:    "ref=news&utm_source=tg",
+			want:     "https://example.com/a?ref=site&x=1&utm_source=tg",
+		},
+		{
+			name:     "override: значение посетителя заменяет",
+			redirect: service.Redirect{URL: "https://example.com/a?ref=site&x=1", QueryPassthrough: model.QueryPassthroughOverride},
+			query:    "ref=news",
+			want:     "https://example.com/a?x=1&ref=news",
+		},
+		{
+			name:     "append: сохраняются оба значения",
+			redirect: service.Redirect{URL: "https://example.com/a?ref=site", QueryPassthrough: model.QueryPassthroughAppend},
+			query:    "ref=news",
+			want:     "https://example.com/a?ref=site&ref=news",
+		},
+		{
+			name:     "кодирование адреса назначения сохраняется",
+			redirect: service.Redirect{URL: "https://example.com/a?sig=a%2Fb", QueryPassthrough: model.QueryPassthroughKeep},
+			query:    "q=a b",
+			want:     "https://example.com/a?sig=a%2Fb&q=a+b",
+		},
+		{
+			name:     "хвост пути дописывается",
+			redirect: service.Redirect{URL: "https://example.com/docs/?v=2", PathPassthrough: true},
+			suffix:   "/guide/a b",
+			want:     "https://example.com/docs/guide/a%20b?v=2",
+		},
+		{
+			name:     "хвост пути не выходит за путь назначения",
+			redirect: service.Redirect{URL: "https://example.com/docs", PathPassthrough: true},
+			suffix:   "/../../admin",
+			want:     "https://example.com/docs/admin",
+		},
+		{
+			name: "путь и параметры вместе, фрагмент на месте",
+			redirect: service.Redirect{
+				URL:              "https://example.com/p#top",
+				QueryPassthrough: model.QueryPassthroughKeep,
+				PathPassthrough:  true,
+			},
+			suffix: "/x",
+			query:  "a=1",
+			want:   "https://example.com/p/x?a=1#top",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, tt.redirect.Location(tt.suffix, query))
+		})
+	}
+}
+
+func TestShorten_InvalidPassthrough(t *testing.T) {
+	ts := setupURLService()
+
+	_, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{QueryPassthrough: "merge"})
+
+	assert.ErrorIs(t, err, service.ErrInvalidPassthrough)
+	ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestResolve_PassthroughSurvivesCache(t *testing.T) {
+	ts := setupURLService()
+	var cached string
+
+	ts.cache.On("Get", mock.Anything, "docs").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "docs").Return(&model.Link{
+		Slug:             "docs",
+		URL:              "https://example.com/docs",
+		QueryPassthrough: model.QueryPassthroughAppend,
+		PathPassthrough:  true,
+	}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "docs", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { cached = args.String(2) }).
+		Return(nil).Once()
+
+	_, err := ts.svc.Resolve(context.Background(), "docs", service.Visitor{})
+	require.NoError(t, err)
+
+	ts.cache.On("Get", mock.Anything, "docs").Return(cached, nil).Once()
+	r, err := ts.svc.Resolve(context.Background(), "docs", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.QueryPassthroughAppend, r.QueryPassthrough)
+	assert.True(t, r.PathPassthrough)
+	ts.repo.AssertNumberOfCalls(t, "GetBySlug", 1)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
+
+// cachedLink — то, что сервис хранит в кэше по slug. Ссылка без срока жизни и особых настроек
+// перехода хранится просто адресом, остальные — JSON-объектом: так записи, сделанные до появления
+// настроек перехода, читаются без миграции кэша.
+type cachedLink struct {
+	URL              string     `json:"u"`
+	RedirectType     int        `json:"t,omitempty"`
+	ExpiresAt        *time.Time `json:"e,omitempty"`
+	QueryPassthrough string     `json:"q,omitempty"`
+	PathPassthrough  bool       `json:"p,omitempty"`
+	UTM              *model.UTM `json:"m,omitempty"`
+	UTMTemplate      string     `json:"mt,omitempty"`
+
+	// Rules хранятся целиком: правило выбирается для каждого посетителя заново
+	Rules []model.LinkRule `json:"r,omitempty"`
+
+	// Targets тоже: вариант разыгрывается на каждом переходе
+	Targets       []model.LinkTarget `json:"ab,omitempty"`
+	StickyTargets bool               `json:"as,omitempty"`
+}
+
+func newCachedLink(link *model.Link) cachedLink {
+	return cachedLink{
+		URL:              link.URL,
+		RedirectType:     link.RedirectType,
+		ExpiresAt:        link.ExpiresAt,
+		QueryPassthrough: link.QueryPassthrough,
+		PathPassthrough:  link.PathPassthrough,
+		UTM:              link.UTM,
+		UTMTemplate:      link.UTMTemplate,
+		Rules:            link.Rules,
+		Targets:          link.Targets,
+		StickyTargets:    link.StickyTargets,
+	}
+}
+
+func (e cachedLink) encode() string {
+	if e.isPlain() {
+		return e.URL
+	}
+	data, _ := json.Marshal(e) // структура из строк, чисел и времени кодируется всегда
+	return string(data)
+}
+
+// isPlain сообщает, что у ссылки нет ничего, кроме адреса назначения.
+func (e cachedLink) isPlain() bool {
+	return e.RedirectType == 0 && e.ExpiresAt == nil && e.QueryPassthrough == "" && !e.PathPassthrough &&
+		e.UTM == nil && e.UTMTemplate == "" && len(e.Rules) == 0 && len(e.Targets) == 0 && !e.StickyTargets
+}
+
+// decodeCachedLink разбирает значение из кэша. Адрес назначения не может начинаться с «{»,
+// поэтому JSON отличается от простого адреса по первому символу.
+func decodeCachedLink(value string) (cachedLink, error) {
+	if !strings.HasPrefix(value, "{") {
+		return cachedLink{URL: value}, nil
+	}
+
+	var e cachedLink
+	if err := json.Unmarshal([]byte(value), &e); err != nil {
+		return cachedLink{}, err
+	}
+	return e, nil
+}
+
+// redirect строит ответ на переход для посетителя v: адрес выбирается правилами ссылки,
+// а если ни одно не подошло — вариантом A/B-разбиения; статус берётся из ссылки, иначе
+// из конфигурации; UTM-метки шаблона подставляются сейчас, поэтому правка шаблона не требует
+// сброса кэша.
+// Постоянное перенаправление браузер запоминает, поэтому срок кэширования ограничен
+// RedirectPermanentMaxAge и не выходит за время жизни ссылки.
+func (s *urlService) redirect(e cachedLink, v Visitor, now time.Time) *Redirect {
+	r := &Redirect{
+		URL:              e.URL,
+		Status:           s.redirectType(e.RedirectType),
+		QueryPassthrough: e.QueryPassthrough,
+		PathPassthrough:  e.PathPassthrough,
+		UTM:              s.utm(e),
+		VaryUserAgent:    len(e.Rules) > 0,
+	}
+	if url, ok := matchRule(e.Rules, v); ok {
+		r.URL = url
+	} else if target, ok := pickTarget(e.Targets, v.Variant, e.StickyTargets); ok {
+		r.URL = target.URL
+		r.Variant = target.Variant
+		if e.StickyTargets {
+			r.VariantMaxAge = s.cfg.VariantCookieMaxAge
+		}
+	}
+
+	// Браузер, запомнивший перенаправление, перестал бы участвовать в разбиении
+	if !model.IsPermanentRedirect(r.Status) || len(e.Targets) > 0 {
+		return r
+	}
+
+	r.MaxAge = s.cfg.RedirectPermanentMaxAge
+	if e.ExpiresAt != nil {
+		if untilExpiry := e.ExpiresAt.Sub(now); untilExpiry < r.MaxAge {
+			r.MaxAge = untilExpiry
+		}
+	}
+	if r.MaxAge < 0 {
+		r.MaxAge = 0
+	}
+	return r
+}
+
+// redirectType возвращает статус перенаправления для типа ссылки; 0 — тип по умолчанию.
+func (s *urlService) redirectType(t int) int {
+	if model.IsValidRedirectType(t) {
+		return t
+	}
+	if model.IsValidRedirectType(s.cfg.RedirectDefaultType) {
+		return s.cfg.RedirectDefaultType
+	}
+	return model.RedirectFound
+}
+
+// validateRedirectType проверяет тип перенаправления из запроса; 0 означает тип по умолчанию.
+func validateRedirectType(t int) error {
+	if t != 0 && !model.IsValidRedirectType(t) {
+		return ErrInvalidRedirectType
+	}
+	return nil
+}
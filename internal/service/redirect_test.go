@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+func withRedirectDefaults(cfg *config.Config) {
+	cfg.RedirectDefaultType = model.RedirectFound
+	cfg.RedirectPermanentMaxAge = 24 * time.Hour
+}
+
+func TestResolve_DefaultRedirectType(t *testing.T) {
+	ts := setupURLServiceWithConfig(withRedirectDefaults)
+
+	ts.cache.On("Get", mock.Anything, "abc").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc", URL: "https://example.com"}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "abc", "https://example.com", time.Duration(0)).Return(nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "abc", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.RedirectFound, r.Status)
+	assert.Zero(t, r.MaxAge, "temporary redirects must not be cached by browsers")
+	ts.cache.AssertExpectations(t)
+}
+
+func TestResolve_PermanentRedirectMaxAgeBoundedByExpiry(t *testing.T) {
+	ts := setupURLServiceWithConfig(withRedirectDefaults)
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	ts.cache.On("Get", mock.Anything, "sale").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "sale").Return(&model.Link{
+		Slug:         "sale",
+		URL:          "https://example.com/sale",
+		ExpiresAt:    &expiresAt,
+		RedirectType: model.RedirectPermanentRedirect,
+	}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "sale", mock.MatchedBy(func(v string) bool {
+		return strings.Contains(v, `"t":308`)
+	}), mock.Anything).Return(nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "sale", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.RedirectPermanentRedirect, r.Status)
+	assert.True(t, r.MaxAge > 0 && r.MaxAge <= 10*time.Minute, "max-age %s must not outlive the link", r.MaxAge)
+	ts.cache.AssertExpectations(t)
+}
+
+func TestResolve_CachedRedirectType(t *testing.T) {
+	ts := setupURLServiceWithConfig(withRedirectDefaults)
+
+	ts.cache.On("Get", mock.Anything, "abc").Return(`{"u":"https://example.com","t":301}`, nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "abc", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, &service.Redirect{
+		URL:    "https://example.com",
+		Status: model.RedirectMovedPermanently,
+		MaxAge: 24 * time.Hour,
+	}, r)
+	ts.repo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+}
+
+func TestResolve_LegacyCacheValueUsesDefault(t *testing.T) {
+	ts := setupURLServiceWithConfig(func(cfg *config.Config) {
+		cfg.RedirectDefaultType = model.RedirectTemporaryRedirect
+	})
+
+	ts.cache.On("Get", mock.Anything, "abc").Return("https://example.com", nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "abc", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", r.URL)
+	assert.Equal(t, model.RedirectTemporaryRedirect, r.Status)
+}
+
+func TestResolve_BrokenCacheValueFallsBackToDB(t *testing.T) {
+	ts := setupURLServiceWithConfig(withRedirectDefaults)
+
+	ts.cache.On("Get", mock.Anything, "abc").Return(`{"u":`, nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc", URL: "https://example.com"}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "abc", "https://example.com", mock.Anything).Return(nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "abc", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", r.URL)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestShorten_InvalidRedirectType(t *testing.T) {
+	ts := setupURLService()
+
+	_, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{RedirectType: 303})
+
+	assert.ErrorIs(t, err, service.ErrInvalidRedirectType)
+	ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestShorten_DoesNotReuseLinkWithOtherRedirectType(t *testing.T) {
+	ts := setupURLService()
+	original := "https://example.com/"
+
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).
+		Return(&model.Link{Slug: "old", URL: original, Status: model.LinkStatusActive}, nil).Once()
+	ts.slugGen.On("Generate", mock.Anything).Return("new", nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "new").Return(nil, repository.ErrNotFound).Once()
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Slug == "new" && l.RedirectType == model.RedirectMovedPermanently
+	})).Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{
+		RedirectType: model.RedirectMovedPermanently,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new", slug)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestUpdateLink_RedirectType(t *testing.T) {
+	ts := setupURLService()
+	stored := &model.Link{Slug: "abc", URL: "https://example.com", RedirectType: model.RedirectMovedPermanently}
+	reset := 0
+
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(stored, nil).Once()
+	ts.repo.On("Update", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.RedirectType == 0
+	})).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "abc").Return(nil).Once()
+
+	link, err := ts.svc.UpdateLink(context.Background(), "", "abc", service.LinkUpdate{RedirectType: &reset})
+
+	require.NoError(t, err)
+	assert.Zero(t, link.RedirectType)
+	ts.repo.AssertExpectations(t)
+	ts.cache.AssertExpectations(t)
+}
+
+func TestUpdateLink_InvalidRedirectType(t *testing.T) {
+	ts := setupURLService()
+	code := 200
+
+	_, err := ts.svc.UpdateLink(context.Background(), "", "abc", service.LinkUpdate{RedirectType: &code})
+
+	assert.ErrorIs(t, err, service.ErrInvalidRedirectType)
+	ts.repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/pkg/useragent"
+)
+
+var (
+	ruleOSes    = []string{useragent.OSiOS, useragent.OSAndroid, useragent.OSWindows, useragent.OSMacOS, useragent.OSChromeOS, useragent.OSLinux, useragent.OSOther}
+	ruleDevices = []string{useragent.DeviceMobile, useragent.DeviceTablet, useragent.DeviceDesktop}
+)
+
+// matchRule возвращает адрес первого правила, под которое подходит посетитель.
+// User-Agent разбирается только для ссылок с правилами.
+func matchRule(rules []model.LinkRule, v Visitor) (string, bool) {
+	if len(rules) == 0 {
+		return "", false
+	}
+
+	client := useragent.Parse(v.UserAgent)
+	for _, rule := range rules {
+		if rule.OS != "" && rule.OS != client.OS {
+			continue
+		}
+		if rule.Device != "" && rule.Device != client.Device {
+			continue
+		}
+		if rule.Bot != nil && *rule.Bot != client.Bot {
+			continue
+		}
+		return rule.URL, true
+	}
+	return "", false
+}
+
+// checkRules проверяет правила перехода из запроса клиента; адрес каждого правила
+// проходит ту же политику, что и адрес назначения ссылки.
+func (s *urlService) checkRules(ctx context.Context, rules []model.LinkRule) error {
+	if err := validateRules(rules); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := s.checkPolicy(ctx, rule.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRules(rules []model.LinkRule) error {
+	if len(rules) > model.MaxLinkRules {
+		return fmt.Errorf("%w: at most %d rules per link", ErrInvalidRule, model.MaxLinkRules)
+	}
+	for i, rule := range rules {
+		switch {
+		case rule.URL == "":
+			return fmt.Errorf("%w: rule %d has no url", ErrInvalidRule, i+1)
+		case rule.OS != "" && !slices.Contains(ruleOSes, rule.OS):
+			return fmt.Errorf("%w: rule %d has unknown os %q", ErrInvalidRule, i+1, rule.OS)
+		case rule.Device != "" && !slices.Contains(ruleDevices, rule.Device):
+			return fmt.Errorf("%w: rule %d has unknown device %q", ErrInvalidRule, i+1, rule.Device)
+		}
+	}
+	return nil
+}
+
+// normalizeRules возвращает nil вместо пустого списка и копирует правила, чтобы ссылка
+// не делила массив с запросом клиента.
+func normalizeRules(rules []model.LinkRule) []model.LinkRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	return slices.Clone(rules)
+}
@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+const (
+	iPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	androidUA = "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+	desktopUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	botUA     = "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"
+)
+
+func TestResolve_Rules(t *testing.T) {
+	human := false
+	rules := []model.LinkRule{
+		{OS: "ios", URL: "https://apps.apple.com/app/id1"},
+		{OS: "android", Device: "mobile", URL: "https://play.google.com/store/apps/details?id=app"},
+		{Device: "desktop", Bot: &human, URL: "https://example.com/desktop"},
+	}
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{"iOS", iPhoneUA, "https://apps.apple.com/app/id1"},
+		{"Android-смартфон", androidUA, "https://play.google.com/store/apps/details?id=app"},
+		{"человек с компьютера", desktopUA, "https://example.com/desktop"},
+		{"робот уходит на адрес по умолчанию", botUA, "https://example.com"},
+		{"без User-Agent", "", "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupURLService()
+			ts.cache.On("Get", mock.Anything, "app").Return("", cache.ErrCacheMiss).Once()
+			ts.repo.On("GetBySlug", mock.Anything, "app").
+				Return(&model.Link{Slug: "app", URL: "https://example.com", Rules: rules}, nil).Once()
+			ts.cache.On("SetNX", mock.Anything, "app", mock.Anything, mock.Anything).Return(nil).Once()
+
+			r, err := ts.svc.Resolve(context.Background(), "app", service.Visitor{UserAgent: tt.userAgent})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.URL)
+			assert.True(t, r.VaryUserAgent)
+		})
+	}
+}
+
+func TestResolve_CacheHoldsRulesNotResolvedURL(t *testing.T) {
+	ts := setupURLService()
+	var cached string
+
+	ts.cache.On("Get", mock.Anything, "app").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "app").Return(&model.Link{
+		Slug:  "app",
+		URL:   "https://example.com",
+		Rules: []model.LinkRule{{OS: "ios", URL: "https://apps.apple.com/app/id1"}},
+	}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "app", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { cached = args.String(2) }).
+		Return(nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "app", service.Visitor{UserAgent: iPhoneUA})
+	require.NoError(t, err)
+	assert.Equal(t, "https://apps.apple.com/app/id1", r.URL)
+	assert.True(t, strings.HasPrefix(cached, "{"), "rules must be cached with the link, got %q", cached)
+
+	// Запись в кэше, сделанная для iPhone, не должна уводить туда же остальных
+	ts.cache.On("Get", mock.Anything, "app").Return(cached, nil).Once()
+	r, err = ts.svc.Resolve(context.Background(), "app", service.Visitor{UserAgent: androidUA})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", r.URL)
+	ts.repo.AssertNumberOfCalls(t, "GetBySlug", 1)
+}
+
+func TestShorten_InvalidRules(t *testing.T) {
+	tooMany := make([]model.LinkRule, model.MaxLinkRules+1)
+	for i := range tooMany {
+		tooMany[i] = model.LinkRule{OS: "ios", URL: "https://example.com/ios"}
+	}
+
+	tests := []struct {
+		name  string
+		rules []model.LinkRule
+	}{
+		{"слишком много правил", tooMany},
+		{"неизвестная ОС", []model.LinkRule{{OS: "symbian", URL: "https://example.com/old"}}},
+		{"неизвестное устройство", []model.LinkRule{{Device: "watch", URL: "https://example.com/watch"}}},
+		{"правило без адреса", []model.LinkRule{{OS: "android"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupURLService()
+
+			_, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{Rules: tt.rules})
+
+			assert.ErrorIs(t, err, service.ErrInvalidRule)
+			ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateLink_ClearsRules(t *testing.T) {
+	ts := setupURLService()
+	stored := &model.Link{
+		Slug:  "app",
+		URL:   "https://example.com",
+		Rules: []model.LinkRule{{OS: "ios", URL: "https://apps.apple.com/app/id1"}},
+	}
+
+	ts.repo.On("GetBySlug", mock.Anything, "app").Return(stored, nil).Once()
+	ts.repo.On("Update", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Rules == nil
+	})).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "app").Return(nil).Once()
+
+	_, err := ts.svc.UpdateLink(context.Background(), "", "app", service.LinkUpdate{Rules: &[]model.LinkRule{}})
+
+	require.NoError(t, err)
+	assert.Len(t, stored.Rules, 1, "stored link must not be modified in place")
+	ts.repo.AssertExpectations(t)
+	ts.cache.AssertExpectations(t)
+}
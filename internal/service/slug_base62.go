@@ -0,0 +1,36 @@
+package service
+
+import (
+	"math/big"
+	"strings"
+)
+
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var big62 = big.NewInt(int64(len(base62Alphabet)))
+
+// encodeBase62 записывает n в системе счисления по основанию 62 символами alphabet.
+// Если width > 0, результат дополняется слева нулевым символом до width.
+func encodeBase62(n *big.Int, alphabet string, width int) string {
+	var digits []byte
+	rem := new(big.Int)
+	for v := new(big.Int).Set(n); v.Sign() > 0; {
+		v.QuoRem(v, big62, rem)
+		digits = append(digits, alphabet[rem.Int64()])
+	}
+	for len(digits) < width || len(digits) == 0 {
+		digits = append(digits, alphabet[0])
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(digits))
+	for i := len(digits) - 1; i >= 0; i-- {
+		sb.WriteByte(digits[i])
+	}
+	return sb.String()
+}
+
+// base62Space возвращает 62^width — количество различных slug длины width.
+func base62Space(width int) *big.Int {
+	return new(big.Int).Exp(big62, big.NewInt(int64(width)), nil)
+}
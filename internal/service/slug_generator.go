@@ -3,9 +3,38 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"fmt"
 	"math/big"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// Стратегии генерации slug (SLUG_STRATEGY)
+const (
+	SlugStrategyRandom     = "random"     // случайная строка из charset
+	SlugStrategySequential = "sequential" // base62 от глобального счётчика
+	SlugStrategyObfuscated = "obfuscated" // перестановка счётчика, slug выглядит случайным
+	SlugStrategyHash       = "hash"       // детерминированный хеш исходного URL
 )
 
+// NewSlugGeneratorFromConfig создаёт генератор выбранной в конфиге стратегии.
+// seq нужен только счётчиковым стратегиям (sequential, obfuscated).
+func NewSlugGeneratorFromConfig(cfg *config.Config, seq repository.SlugSequence) (SlugGenerator, error) {
+	switch cfg.SlugStrategy {
+	case SlugStrategyRandom, "":
+		return NewSlugGenerator(cfg.SlugLength), nil
+	case SlugStrategySequential:
+		return NewSequentialSlugGenerator(seq), nil
+	case SlugStrategyObfuscated:
+		return NewObfuscatedSlugGenerator(seq, cfg.SlugLength, cfg.SlugSalt)
+	case SlugStrategyHash:
+		return NewHashSlugGenerator(cfg.SlugLength), nil
+	default:
+		return nil, fmt.Errorf("invalid slug strategy: %s", cfg.SlugStrategy)
+	}
+}
+
 type defaultSlugGenerator struct {
 	slugLength int
 }
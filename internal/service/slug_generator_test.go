@@ -0,0 +1,147 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestSequentialSlugGenerator(t *testing.T) {
+	gen := service.NewSequentialSlugGenerator(&mem.Sequence{})
+	ctx := context.Background()
+
+	var slugs []string
+	for i := 0; i < 62; i++ {
+		slug, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		slugs = append(slugs, slug)
+	}
+
+	assert.Equal(t, "1", slugs[0])
+	assert.Equal(t, "Z", slugs[60])
+	assert.Equal(t, "10", slugs[61])
+}
+
+func TestObfuscatedSlugGenerator(t *testing.T) {
+	ctx := context.Background()
+	gen, err := service.NewObfuscatedSlugGenerator(&mem.Sequence{}, 6, "secret")
+	require.NoError(t, err)
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 5000; i++ {
+		slug, err := gen.Generate(ctx)
+		require.NoError(t, err)
+		require.Len(t, slug, 6)
+		_, dup := seen[slug]
+		require.False(t, dup, "duplicate slug %q", slug)
+		seen[slug] = struct{}{}
+	}
+
+	// Та же соль и тот же счётчик дают тот же slug, другая соль — другой
+	same, _ := service.NewObfuscatedSlugGenerator(&mem.Sequence{}, 6, "secret")
+	other, _ := service.NewObfuscatedSlugGenerator(&mem.Sequence{}, 6, "another")
+	a, _ := same.Generate(ctx)
+	b, _ := other.Generate(ctx)
+	assert.Contains(t, seen, a)
+	assert.NotEqual(t, a, b)
+}
+
+func TestHashSlugGenerator_RequiresURL(t *testing.T) {
+	gen := service.NewHashSlugGenerator(8)
+
+	_, err := gen.Generate(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestNewSlugGeneratorFromConfig(t *testing.T) {
+	for _, strategy := range []string{"", "random", "sequential", "obfuscated", "hash"} {
+		_, err := service.NewSlugGeneratorFromConfig(&config.Config{SlugStrategy: strategy, SlugLength: 8}, &mem.Sequence{})
+		assert.NoError(t, err, strategy)
+	}
+
+	_, err := service.NewSlugGeneratorFromConfig(&config.Config{SlugStrategy: "uuid"}, &mem.Sequence{})
+	assert.Error(t, err)
+}
+
+func newServiceWithGenerator(repo *mocks.MockURLRepository, gen service.SlugGenerator) service.URLService {
+	log := new(mocks.MockLogger)
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	cfg := &config.Config{MaxAttempts: 5, SlugLength: 8}
+	return service.NewURLService(repo, log, new(mocks.MockCache), cfg, gen, nil)
+}
+
+func TestShorten_CollisionFreeGenerator_SkipsProbe(t *testing.T) {
+	repo := new(mocks.MockURLRepository)
+	svc := newServiceWithGenerator(repo, service.NewSequentialSlugGenerator(&mem.Sequence{}))
+	original := "https://example.com/"
+
+	repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound).Once()
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Slug == "1"
+	})).Return(nil).Once()
+
+	slug, err := svc.Shorten(context.Background(), original, service.ShortenOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "1", slug)
+	repo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestShorten_HashGenerator_Deterministic(t *testing.T) {
+	original := "https://example.com/page"
+	var created []string
+
+	for i := 0; i < 2; i++ {
+		repo := new(mocks.MockURLRepository)
+		svc := newServiceWithGenerator(repo, service.NewHashSlugGenerator(8))
+
+		repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound).Once()
+		repo.On("GetBySlug", mock.Anything, mock.Anything).Return(nil, repository.ErrNotFound).Once()
+		repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		slug, err := svc.Shorten(context.Background(), original, service.ShortenOptions{})
+		require.NoError(t, err)
+		require.Len(t, slug, 8)
+		created = append(created, slug)
+	}
+
+	assert.Equal(t, created[0], created[1])
+}
+
+func TestShorten_HashGenerator_CollisionChangesSlug(t *testing.T) {
+	repo := new(mocks.MockURLRepository)
+	svc := newServiceWithGenerator(repo, service.NewHashSlugGenerator(8))
+	original := "https://example.com/page"
+
+	var probed []string
+	repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound).Once()
+	repo.On("GetBySlug", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		probed = append(probed, args.String(1))
+	}).Return(&model.Link{}, nil).Once() // занят другой ссылкой
+	repo.On("GetBySlug", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		probed = append(probed, args.String(1))
+	}).Return(nil, repository.ErrNotFound).Once()
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+	slug, err := svc.Shorten(context.Background(), original, service.ShortenOptions{})
+
+	require.NoError(t, err)
+	require.Len(t, probed, 2)
+	assert.NotEqual(t, probed[0], probed[1])
+	assert.Equal(t, probed[1], slug)
+}
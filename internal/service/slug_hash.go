@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// hashSlugGenerator выводит slug из хеша исходного URL: одинаковые URL получают
+// одинаковый slug. Коллизии возможны (slug короче хеша), поэтому при повторных
+// попытках к URL подмешивается номер попытки.
+type hashSlugGenerator struct {
+	slugLength int
+}
+
+var errNoSlugInput = errors.New("hash slug strategy requires the original URL")
+
+func NewHashSlugGenerator(slugLength int) SlugGenerator {
+	return &hashSlugGenerator{slugLength: slugLength}
+}
+
+func (g *hashSlugGenerator) Generate(ctx context.Context) (string, error) {
+	in := slugInputFromContext(ctx)
+	if in == nil || in.url == "" {
+		return "", errNoSlugInput
+	}
+
+	data := in.url
+	if in.attempt > 0 {
+		data += "#" + strconv.Itoa(in.attempt)
+	}
+	in.attempt++
+
+	sum := sha256.Sum256([]byte(data))
+	n := new(big.Int).SetBytes(sum[:])
+	n.Mod(n, base62Space(g.slugLength))
+	return encodeBase62(n, base62Alphabet, g.slugLength), nil
+}
+
+type slugInputKey struct{}
+
+// slugInput — данные создаваемой ссылки, доступные генератору через контекст.
+type slugInput struct {
+	url     string
+	attempt int // сколько slug уже выдано для этой ссылки
+}
+
+func withSlugInput(ctx context.Context, url string) context.Context {
+	return context.WithValue(ctx, slugInputKey{}, &slugInput{url: url})
+}
+
+func slugInputFromContext(ctx context.Context) *slugInput {
+	in, _ := ctx.Value(slugInputKey{}).(*slugInput)
+	return in
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// obfuscatedSlugGenerator превращает значение счётчика в slug фиксированной длины,
+// похожий на случайный (в духе Sqids/Hashids). Счётчик переставляется аффинным
+// преобразованием id*a + b по модулю 62^length, а цифры записываются перемешанным
+// алфавитом. Коэффициенты и алфавит выводятся из соли, поэтому без неё порядок
+// ссылок по slug не восстановить. Преобразование взаимно однозначно, так что
+// разные значения счётчика дают разные slug.
+type obfuscatedSlugGenerator struct {
+	seq      repository.SlugSequence
+	length   int
+	space    *big.Int // 62^length
+	mul      *big.Int // взаимно прост с 62^length
+	offset   *big.Int
+	alphabet string
+}
+
+var errSlugSpaceExhausted = errors.New("slug space exhausted")
+
+func NewObfuscatedSlugGenerator(seq repository.SlugSequence, length int, salt string) (SlugGenerator, error) {
+	if length <= 0 {
+		return nil, fmt.Errorf("invalid slug length: %d", length)
+	}
+
+	space := base62Space(length)
+	seed := sha256.Sum256([]byte("slugkiller:" + salt))
+
+	// 62 = 2*31, поэтому множитель взаимно прост с 62^length, если он нечётный и не делится на 31.
+	mul := new(big.Int).SetBytes(seed[:16])
+	mul.Mod(mul, space)
+	mul.SetBit(mul, 0, 1)
+	for new(big.Int).Mod(mul, big.NewInt(31)).Sign() == 0 {
+		mul.Add(mul, big.NewInt(2))
+		mul.Mod(mul, space)
+	}
+
+	offset := new(big.Int).SetBytes(seed[16:])
+	offset.Mod(offset, space)
+
+	return &obfuscatedSlugGenerator{
+		seq:      seq,
+		length:   length,
+		space:    space,
+		mul:      mul,
+		offset:   offset,
+		alphabet: shuffleAlphabet(base62Alphabet, seed),
+	}, nil
+}
+
+func (g *obfuscatedSlugGenerator) Generate(ctx context.Context) (string, error) {
+	id, err := g.seq.NextSlugID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	n := big.NewInt(id)
+	if n.Cmp(g.space) >= 0 {
+		return "", fmt.Errorf("%w: counter %d does not fit into %d characters", errSlugSpaceExhausted, id, g.length)
+	}
+
+	n.Mul(n, g.mul)
+	n.Add(n, g.offset)
+	n.Mod(n, g.space)
+	return encodeBase62(n, g.alphabet, g.length), nil
+}
+
+// CollisionFree: перестановка взаимно однозначна, а счётчик не повторяется.
+func (g *obfuscatedSlugGenerator) CollisionFree() bool { return true }
+
+// shuffleAlphabet детерминированно перемешивает алфавит (Фишер–Йетс) на основе seed.
+func shuffleAlphabet(alphabet string, seed [sha256.Size]byte) string {
+	b := []byte(alphabet)
+	state := seed
+	for i := len(b) - 1; i > 0; i-- {
+		if i%8 == 0 {
+			state = sha256.Sum256(state[:])
+		}
+		r := binary.BigEndian.Uint32(state[(i%8)*4:])
+		j := int(r % uint32(i+1))
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
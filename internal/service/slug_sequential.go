@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// sequentialSlugGenerator кодирует значение глобального счётчика в base62:
+// самые короткие slug из возможных, но по ним видно порядок и количество ссылок.
+type sequentialSlugGenerator struct {
+	seq repository.SlugSequence
+}
+
+func NewSequentialSlugGenerator(seq repository.SlugSequence) SlugGenerator {
+	return &sequentialSlugGenerator{seq: seq}
+}
+
+func (g *sequentialSlugGenerator) Generate(ctx context.Context) (string, error) {
+	id, err := g.seq.NextSlugID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return encodeBase62(big.NewInt(id), base62Alphabet, 0), nil
+}
+
+// CollisionFree: каждое значение счётчика выдаётся один раз.
+func (g *sequentialSlugGenerator) CollisionFree() bool { return true }
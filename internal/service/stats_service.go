@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// Ограничения на размер ответа статистики
+const (
+	maxStatsBuckets = 24 * 31 // месяц почасовых точек или два года дневных не отдаём
+	maxStatsLimit   = 100
+)
+
+type statsService struct {
+	links  repository.URLReader
+	stats  repository.StatsReader
+	admins admins
+	logger logger.Logger
+}
+
+// NewStatsService создаёт сервис статистики; adminOwners видят статистику любых ссылок (ADMIN_OWNERS).
+func NewStatsService(links repository.URLReader, stats repository.StatsReader, adminOwners []string, l logger.Logger) StatsService {
+	return &statsService{
+		links:  links,
+		stats:  stats,
+		admins: newAdmins(adminOwners),
+		logger: l,
+	}
+}
+
+// LinkStats проверяет параметры выборки, существование ссылки и её владельца, а затем дополняет
+// разреженный временной ряд из хранилища нулевыми точками, чтобы он был непрерывным.
+func (s *statsService) LinkStats(ctx context.Context, ownerID, slug string, q model.StatsQuery) (*model.LinkStats, error) {
+	if err := validateStatsQuery(q); err != nil {
+		return nil, err
+	}
+
+	link, err := s.links.GetBySlug(ctx, slug)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) && !errors.Is(err, repository.ErrUnavailable) {
+			s.logger.Error("Failed to fetch link for stats", err, map[string]interface{}{"slug": slug})
+		}
+		return nil, err
+	}
+	if !s.admins.canManage(link, ownerID) {
+		return nil, repository.ErrNotFound
+	}
+
+	stats, err := s.stats.LinkStats(ctx, slug, q)
+	if err != nil {
+		// Открытый breaker уже залогирован при переходе
+		if !errors.Is(err, repository.ErrUnavailable) {
+			s.logger.Error("Failed to load link stats", err, map[string]interface{}{"slug": slug})
+		}
+		return nil, err
+	}
+
+	stats.Series = fillSeries(stats.Series, q)
+	return stats, nil
+}
+
+func validateStatsQuery(q model.StatsQuery) error {
+	if q.Interval != model.StatsIntervalHour && q.Interval != model.StatsIntervalDay {
+		return fmt.Errorf("%w: interval must be %q or %q", ErrInvalidStatsQuery, model.StatsIntervalHour, model.StatsIntervalDay)
+	}
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidStatsQuery)
+	}
+	if q.Limit <= 0 || q.Limit > maxStatsLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidStatsQuery, maxStatsLimit)
+	}
+
+	step := model.IntervalDuration(q.Interval)
+	if q.To.Sub(q.From)/step > maxStatsBuckets {
+		return fmt.Errorf("%w: range is too large for %s interval", ErrInvalidStatsQuery, q.Interval)
+	}
+	return nil
+}
+
+// fillSeries возвращает ряд с точкой на каждый шаг интервала [q.From, q.To) в UTC.
+func fillSeries(sparse []model.StatsBucket, q model.StatsQuery) []model.StatsBucket {
+	step := model.IntervalDuration(q.Interval)
+
+	byStart := make(map[time.Time]model.StatsBucket, len(sparse))
+	for _, b := range sparse {
+		byStart[b.Start.UTC()] = b
+	}
+
+	var series []model.StatsBucket
+	for start := q.From.UTC().Truncate(step); start.Before(q.To); start = start.Add(step) {
+		b, ok := byStart[start]
+		if !ok {
+			b = model.StatsBucket{Start: start}
+		}
+		b.Start = start
+		series = append(series, b)
+	}
+	return series
+}
@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func setupStatsService() (service.StatsService, *mocks.MockStore) {
+	store := new(mocks.MockStore)
+	log := new(mocks.MockLogger)
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return service.NewStatsService(store, store, []string{"admin"}, log), store
+}
+
+func TestLinkStats_FillsSeriesGaps(t *testing.T) {
+	svc, store := setupStatsService()
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := model.StatsQuery{From: from, To: from.Add(3 * 24 * time.Hour), Interval: model.StatsIntervalDay, Limit: 10}
+
+	store.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc"}, nil).Once()
+	store.On("LinkStats", mock.Anything, "abc", q).Return(&model.LinkStats{
+		TotalClicks:    3,
+		UniqueVisitors: 2,
+		Series: []model.StatsBucket{
+			{Start: from.Add(24 * time.Hour), Clicks: 3, UniqueVisitors: 2},
+		},
+	}, nil).Once()
+
+	stats, err := svc.LinkStats(context.Background(), "", "abc", q)
+
+	require.NoError(t, err)
+	assert.Equal(t, []model.StatsBucket{
+		{Start: from},
+		{Start: from.Add(24 * time.Hour), Clicks: 3, UniqueVisitors: 2},
+		{Start: from.Add(48 * time.Hour)},
+	}, stats.Series)
+	store.AssertExpectations(t)
+}
+
+func TestLinkStats_NotFound(t *testing.T) {
+	svc, store := setupStatsService()
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := model.StatsQuery{From: from, To: from.Add(time.Hour), Interval: model.StatsIntervalHour, Limit: 10}
+
+	store.On("GetBySlug", mock.Anything, "missing").Return(nil, repository.ErrNotFound).Once()
+
+	_, err := svc.LinkStats(context.Background(), "", "missing", q)
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	store.AssertNotCalled(t, "LinkStats", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLinkStats_OtherOwner(t *testing.T) {
+	svc, store := setupStatsService()
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := model.StatsQuery{From: from, To: from.Add(time.Hour), Interval: model.StatsIntervalHour, Limit: 10}
+
+	store.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc", OwnerID: "team-a"}, nil).Once()
+
+	_, err := svc.LinkStats(context.Background(), "team-b", "abc", q)
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	store.AssertNotCalled(t, "LinkStats", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLinkStats_AdminSeesAnyLink(t *testing.T) {
+	svc, store := setupStatsService()
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := model.StatsQuery{From: from, To: from.Add(time.Hour), Interval: model.StatsIntervalHour, Limit: 10}
+
+	store.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc"}, nil).Once()
+	store.On("LinkStats", mock.Anything, "abc", q).Return(&model.LinkStats{}, nil).Once()
+
+	_, err := svc.LinkStats(context.Background(), "admin", "abc", q)
+
+	assert.NoError(t, err)
+	store.AssertExpectations(t)
+}
+
+func TestLinkStats_InvalidQuery(t *testing.T) {
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]model.StatsQuery{
+		"unknown interval": {From: from, To: from.Add(time.Hour), Interval: "week", Limit: 10},
+		"empty range":      {From: from, To: from, Interval: model.StatsIntervalDay, Limit: 10},
+		"zero limit":       {From: from, To: from.Add(time.Hour), Interval: model.StatsIntervalDay},
+		"too many buckets": {From: from, To: from.Add(365 * 24 * time.Hour), Interval: model.StatsIntervalHour, Limit: 10},
+	}
+
+	for name, q := range cases {
+		t.Run(name, func(t *testing.T) {
+			svc, store := setupStatsService()
+
+			_, err := svc.LinkStats(context.Background(), "", "abc", q)
+
+			assert.ErrorIs(t, err, service.ErrInvalidStatsQuery)
+			store.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
+
+// pickTarget выбирает вариант A/B-разбиения: закреплённый за посетителем, если ссылка это
+// разрешает и такой вариант ещё есть, иначе — случайный с учётом весов.
+func pickTarget(targets []model.LinkTarget, assigned string, sticky bool) (model.LinkTarget, bool) {
+	if len(targets) == 0 {
+		return model.LinkTarget{}, false
+	}
+
+	if sticky && assigned != "" {
+		for _, t := range targets {
+			if t.Variant == assigned {
+				return t, true
+			}
+		}
+	}
+
+	total := 0
+	for _, t := range targets {
+		total += t.Weight
+	}
+	if total <= 0 {
+		// Веса проверяются при сохранении; на случай старых данных берём первый вариант
+		return targets[0], true
+	}
+
+	n := rand.IntN(total)
+	for _, t := range targets {
+		if n < t.Weight {
+			return t, true
+		}
+		n -= t.Weight
+	}
+	return targets[len(targets)-1], true
+}
+
+// checkTargets проверяет варианты A/B-разбиения из запроса клиента; адрес каждого варианта
+// проходит ту же политику, что и адрес назначения ссылки.
+func (s *urlService) checkTargets(ctx context.Context, targets []model.LinkTarget) error {
+	if err := validateTargets(targets); err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if err := s.checkPolicy(ctx, t.URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateTargets(targets []model.LinkTarget) error {
+	if len(targets) == 1 || len(targets) > model.MaxLinkTargets {
+		return fmt.Errorf("%w: a split needs from 2 to %d targets", ErrInvalidTargets, model.MaxLinkTargets)
+	}
+
+	seen := make(map[string]bool, len(targets))
+	for i, t := range targets {
+		switch {
+		case t.URL == "":
+			return fmt.Errorf("%w: target %d has no url", ErrInvalidTargets, i+1)
+		case t.Weight <= 0:
+			return fmt.Errorf("%w: target %d must have a positive weight", ErrInvalidTargets, i+1)
+		case !isValidVariant(t.Variant):
+			return fmt.Errorf("%w: target %d variant must be 1-%d letters, digits, '_' or '-'",
+				ErrInvalidTargets, i+1, model.MaxVariantLength)
+		case seen[t.Variant]:
+			return fmt.Errorf("%w: duplicate variant %q", ErrInvalidTargets, t.Variant)
+		}
+		seen[t.Variant] = true
+	}
+	return nil
+}
+
+// isValidVariant проверяет имя варианта: оно уходит в cookie и в статистику,
+// поэтому допускаются те же символы, что и в алиасе.
+func isValidVariant(variant string) bool {
+	if variant == "" || len(variant) > model.MaxVariantLength {
+		return false
+	}
+	for _, r := range variant {
+		if !isAliasRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTargets возвращает nil вместо пустого списка и копирует варианты, чтобы ссылка
+// не делила массив с запросом клиента.
+func normalizeTargets(targets []model.LinkTarget) []model.LinkTarget {
+	if len(targets) == 0 {
+		return nil
+	}
+	return slices.Clone(targets)
+}
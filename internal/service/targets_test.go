@@ -0,0 +1,136 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+const splitCacheValue = `{"u":"https://example.com","ab":[` +
+	`{"variant":"a","url":"https://example.com/a","weight":70},` +
+	`{"variant":"b","url":"https://example.com/b","weight":30}]`
+
+func withVariantCookie(cfg *config.Config) {
+	withRedirectDefaults(cfg)
+	cfg.VariantCookieMaxAge = 30 * 24 * time.Hour
+}
+
+func TestResolve_WeightedTargets(t *testing.T) {
+	ts := setupURLServiceWithConfig(withVariantCookie)
+	ts.cache.On("Get", mock.Anything, "split").Return(splitCacheValue+`}`, nil)
+
+	const runs = 2000
+	counts := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		r, err := ts.svc.Resolve(context.Background(), "split", service.Visitor{})
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/"+r.Variant, r.URL)
+		assert.Zero(t, r.VariantMaxAge, "variant must not be pinned without sticky_targets")
+		counts[r.Variant]++
+	}
+
+	assert.Len(t, counts, 2)
+	// 70% ± 7 п.п.: вероятность выйти за границы при честном выборе пренебрежимо мала
+	assert.InDelta(t, 0.7, float64(counts["a"])/runs, 0.07, "counts: %v", counts)
+}
+
+func TestResolve_StickyVariant(t *testing.T) {
+	ts := setupURLServiceWithConfig(withVariantCookie)
+	ts.cache.On("Get", mock.Anything, "split").Return(splitCacheValue+`,"as":true}`, nil)
+
+	for i := 0; i < 20; i++ {
+		r, err := ts.svc.Resolve(context.Background(), "split", service.Visitor{Variant: "b"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/b", r.URL)
+		assert.Equal(t, "b", r.Variant)
+		assert.Equal(t, 30*24*time.Hour, r.VariantMaxAge)
+	}
+
+	// Вариант, которого у ссылки больше нет, разыгрывается заново
+	r, err := ts.svc.Resolve(context.Background(), "split", service.Visitor{Variant: "removed"})
+	require.NoError(t, err)
+	assert.Contains(t, []string{"a", "b"}, r.Variant)
+}
+
+func TestResolve_TargetsCachedWithLink(t *testing.T) {
+	ts := setupURLServiceWithConfig(withVariantCookie)
+
+	ts.cache.On("Get", mock.Anything, "split").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "split").Return(&model.Link{
+		Slug:         "split",
+		URL:          "https://example.com",
+		RedirectType: model.RedirectMovedPermanently,
+		Targets: []model.LinkTarget{
+			{Variant: "a", URL: "https://example.com/a", Weight: 1},
+			{Variant: "b", URL: "https://example.com/b", Weight: 1},
+		},
+	}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "split", mock.MatchedBy(func(v string) bool {
+		return strings.Contains(v, `"ab":[`)
+	}), mock.Anything).Return(nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "split", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.RedirectMovedPermanently, r.Status)
+	assert.Zero(t, r.MaxAge, "browsers must not pin a split redirect")
+	ts.cache.AssertExpectations(t)
+}
+
+func TestResolve_RuleWinsOverTargets(t *testing.T) {
+	ts := setupURLService()
+	ts.cache.On("Get", mock.Anything, "split").Return(splitCacheValue+
+		`,"r":[{"os":"ios","url":"https://apps.apple.com/app/id1"}]}`, nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "split", service.Visitor{UserAgent: iPhoneUA})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://apps.apple.com/app/id1", r.URL)
+	assert.Empty(t, r.Variant)
+}
+
+func TestShorten_InvalidTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		targets []model.LinkTarget
+	}{
+		{"один вариант", []model.LinkTarget{{Variant: "a", URL: "https://example.com/a", Weight: 1}}},
+		{"нулевой вес", []model.LinkTarget{
+			{Variant: "a", URL: "https://example.com/a", Weight: 1},
+			{Variant: "b", URL: "https://example.com/b"},
+		}},
+		{"повтор варианта", []model.LinkTarget{
+			{Variant: "a", URL: "https://example.com/a", Weight: 1},
+			{Variant: "a", URL: "https://example.com/b", Weight: 1},
+		}},
+		{"недопустимое имя варианта", []model.LinkTarget{
+			{Variant: "a;b", URL: "https://example.com/a", Weight: 1},
+			{Variant: "c", URL: "https://example.com/c", Weight: 1},
+		}},
+		{"вариант без адреса", []model.LinkTarget{
+			{Variant: "a", URL: "https://example.com/a", Weight: 1},
+			{Variant: "b", Weight: 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupURLService()
+
+			_, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{Targets: tt.targets})
+
+			assert.ErrorIs(t, err, service.ErrInvalidTargets)
+			ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// spanError возвращает err, только если это сбой. Ожидаемые исходы — отсутствующая или
+// истёкшая ссылка, отклонённый запрос — спан ошибкой не помечают.
+func spanError(err error) error {
+	switch {
+	case err == nil,
+		IsRejected(err),
+		errors.Is(err, repository.ErrNotFound),
+		errors.Is(err, repository.ErrAlreadyExists),
+		errors.Is(err, ErrLinkExpired),
+		errors.Is(err, ErrLinkDisabled):
+		return nil
+	}
+	return err
+}
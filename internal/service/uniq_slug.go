@@ -13,6 +13,15 @@ func (s *urlService) generateUniqueSlug(ctx context.Context) (string, error) {
 		if err != nil {
 			return "", err
 		}
+		if s.isReservedSlug(slug) {
+			continue
+		}
+
+		// Генератор без коллизий не выдаст занятый slug; столкновение с алиасом
+		// всё равно поймает Create, вернув ErrAlreadyExists.
+		if s.collisionFree() {
+			return slug, nil
+		}
 
 		_, err = s.repo.GetBySlug(ctx, slug)
 		if err != nil {
@@ -25,3 +34,8 @@ func (s *urlService) generateUniqueSlug(ctx context.Context) (string, error) {
 	}
 	return "", errors.New("could not generate unique slug after several attempts")
 }
+
+func (s *urlService) collisionFree() bool {
+	g, ok := s.slugGen.(CollisionFreeSlugGenerator)
+	return ok && g.CollisionFree()
+}
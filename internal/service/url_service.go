@@ -3,13 +3,18 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/Thoustick/SlugKiller/config"
 	"github.com/Thoustick/SlugKiller/internal/cache"
 	"github.com/Thoustick/SlugKiller/internal/model"
 	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/tracing"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
 type urlService struct {
@@ -18,14 +23,20 @@ type urlService struct {
 	logger  logger.Logger
 	cfg     *config.Config
 	slugGen SlugGenerator
+	policy  URLPolicy
+	admins  admins
+
+	resolving singleflight.Group // объединяет одновременные промахи кэша по одному slug
 }
 
+// NewURLService создаёт сервис ссылок. policy может быть nil — тогда адреса не проверяются.
 func NewURLService(
 	r repository.URLRepository,
 	l logger.Logger,
 	c cache.URLCache,
 	cfg *config.Config,
 	slugGen SlugGenerator,
+	policy URLPolicy,
 ) URLService {
 	return &urlService{
 		repo:    r,
@@ -33,10 +44,13 @@ func NewURLService(
 		logger:  l,
 		cfg:     cfg,
 		slugGen: slugGen,
+		policy:  policy,
+		admins:  newAdmins(cfg.AdminOwners),
 	}
 }
 
-func (s *urlService) CreateUniqueSlugLoop(ctx context.Context, originalURL string) (string, error) {
+func (s *urlService) CreateUniqueSlugLoop(ctx context.Context, originalURL string, opts ShortenOptions) (string, error) {
+	ctx = withSlugInput(ctx, originalURL)
 	for i := 0; i < s.cfg.MaxAttempts; i++ {
 		slug, err := s.generateUniqueSlug(ctx)
 		if err != nil {
@@ -48,13 +62,26 @@ func (s *urlService) CreateUniqueSlugLoop(ctx context.Context, originalURL strin
 		}
 
 		link := &model.Link{
-			Slug:      slug,
-			URL:       originalURL,
-			CreatedAt: time.Now(),
+			Slug:         slug,
+			URL:          originalURL,
+			CanonicalURL: s.canonicalURL(originalURL),
+			CreatedAt:    time.Now(),
+			ExpiresAt:    opts.ExpiresAt,
+			OwnerID:      opts.OwnerID,
+			RedirectType: opts.RedirectType,
+
+			QueryPassthrough: opts.QueryPassthrough,
+			PathPassthrough:  opts.PathPassthrough,
+			UTM:              normalizeUTM(opts.UTM),
+			UTMTemplate:      opts.UTMTemplate,
+			Rules:            normalizeRules(opts.Rules),
+			Targets:          normalizeTargets(opts.Targets),
+			StickyTargets:    opts.StickyTargets,
 		}
 
 		err = s.repo.Create(ctx, link)
 		if err == nil {
+			s.forgetNotFound(ctx, slug)
 			return slug, nil
 		}
 		if errors.Is(err, repository.ErrAlreadyExists) {
@@ -74,25 +101,86 @@ func (s *urlService) CreateUniqueSlugLoop(ctx context.Context, originalURL strin
 	return "", errors.New("failed to generate unique slug after max attempts")
 }
 
-// Shorten проверяет, есть ли уже запись для originalURL.
+// Shorten проверяет, есть ли уже бессрочная запись для originalURL (с точностью до канонического вида).
 // Если есть, возвращает существующий slug.
-// Если нет, генерирует уникальный slug и сохраняет новую запись в базе.
-func (s *urlService) Shorten(ctx context.Context, originalURL string) (string, error) {
+// Если нет (или для ссылки задан срок жизни), генерирует уникальный slug и сохраняет новую запись в базе.
+func (s *urlService) Shorten(ctx context.Context, originalURL string, opts ShortenOptions) (string, error) {
+	ctx, span := tracing.Start(ctx, "urlService.Shorten", attribute.Bool("link.alias", opts.Alias != ""))
+	slug, err := s.shorten(ctx, originalURL, opts)
+	span.SetAttributes(attribute.String("link.slug", slug))
+	tracing.End(span, spanError(err))
+	return slug, err
+}
+
+func (s *urlService) shorten(ctx context.Context, originalURL string, opts ShortenOptions) (string, error) {
 	if originalURL == "" {
 		s.logger.Warn("Attempted to shorten empty URL", nil)
-		return "", errors.New("empty URL provided")
+		return "", ErrEmptyURL
+	}
+	if opts.ExpiresAt != nil && !opts.ExpiresAt.After(time.Now()) {
+		s.logger.Warn("Attempted to shorten URL with expiration in the past", map[string]interface{}{
+			"url":        originalURL,
+			"expires_at": *opts.ExpiresAt,
+		})
+		return "", ErrInvalidExpiry
+	}
+	if err := validateRedirectType(opts.RedirectType); err != nil {
+		s.logger.Warn("Attempted to shorten URL with invalid redirect type", map[string]interface{}{
+			"url":           originalURL,
+			"redirect_type": opts.RedirectType,
+		})
+		return "", err
+	}
+	if err := validatePassthrough(opts.QueryPassthrough); err != nil {
+		s.logger.Warn("Attempted to shorten URL with invalid query passthrough", map[string]interface{}{
+			"url":               originalURL,
+			"query_passthrough": opts.QueryPassthrough,
+		})
+		return "", err
+	}
+	if err := s.validateUTMTemplate(opts.UTMTemplate); err != nil {
+		s.logger.Warn("Attempted to shorten URL with unknown UTM template", map[string]interface{}{
+			"url":          originalURL,
+			"utm_template": opts.UTMTemplate,
+		})
+		return "", err
+	}
+	if err := s.checkPolicy(ctx, originalURL); err != nil {
+		return "", err
+	}
+	if err := s.checkRules(ctx, opts.Rules); err != nil {
+		s.logger.Warn("Attempted to shorten URL with invalid redirect rules", map[string]interface{}{
+			"url":   originalURL,
+			"rules": len(opts.Rules),
+		})
+		return "", err
+	}
+	if err := s.checkTargets(ctx, opts.Targets); err != nil {
+		s.logger.Warn("Attempted to shorten URL with invalid split targets", map[string]interface{}{
+			"url":     originalURL,
+			"targets": len(opts.Targets),
+		})
+		return "", err
 	}
 
-	// 1. Проверяем, нет ли уже записи
-	existingLink, err := s.repo.GetByOriginalURL(ctx, originalURL)
-	if err != nil {
-		if !errors.Is(err, repository.ErrNotFound) { // <--- вот правильная проверка
+	if opts.Alias != "" {
+		return s.createWithAlias(ctx, originalURL, opts)
+	}
+
+	// 1. Проверяем, нет ли уже записи. Ссылки со сроком жизни не переиспользуем:
+	// у каждой из них своё время истечения. Ссылку с другими настройками перехода — тоже.
+	var existingLink *model.Link
+	if opts.ExpiresAt == nil {
+		link, err := s.repo.GetByCanonicalURL(ctx, opts.OwnerID, s.canonicalURL(originalURL))
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
 			s.logger.Error("Failed to check existing URL", err, map[string]interface{}{
 				"url": originalURL,
 			})
 			return "", err
 		}
-		existingLink = nil // просто URL ещё не сокращали, это нормально
+		if link != nil && link.ExpiresAt == nil && link.IsActive() && sameRedirectSettings(link, opts) {
+			existingLink = link
+		}
 	}
 
 	// 2. Если запись уже есть — возвращаем slug
@@ -105,7 +193,7 @@ func (s *urlService) Shorten(ctx context.Context, originalURL string) (string, e
 	}
 
 	// 3. Генерируем уникальный slug
-	slug, err := s.CreateUniqueSlugLoop(ctx, originalURL)
+	slug, err := s.CreateUniqueSlugLoop(ctx, originalURL, opts)
 	if err != nil {
 		return "", err
 	}
@@ -117,18 +205,132 @@ func (s *urlService) Shorten(ctx context.Context, originalURL string) (string, e
 	return slug, nil
 }
 
+// sameRedirectSettings сообщает, переходит ли существующая ссылка так, как просит клиент.
+func sameRedirectSettings(link *model.Link, opts ShortenOptions) bool {
+	return link.RedirectType == opts.RedirectType &&
+		link.QueryPassthrough == opts.QueryPassthrough &&
+		link.PathPassthrough == opts.PathPassthrough &&
+		link.UTMTemplate == opts.UTMTemplate &&
+		sameUTM(link.UTM, normalizeUTM(opts.UTM)) &&
+		slices.EqualFunc(link.Rules, opts.Rules, model.LinkRule.Equal) &&
+		slices.Equal(link.Targets, opts.Targets) &&
+		link.StickyTargets == opts.StickyTargets
+}
+
+func sameUTM(a, b *model.UTM) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// checkPolicy проверяет адрес назначения политикой безопасности, если она задана.
+func (s *urlService) checkPolicy(ctx context.Context, originalURL string) error {
+	if s.policy == nil {
+		return nil
+	}
+
+	err := s.policy.Check(ctx, originalURL)
+	var violation *URLPolicyError
+	if errors.As(err, &violation) {
+		s.logger.Warn("URL rejected by policy", map[string]interface{}{
+			"url":    originalURL,
+			"reason": violation.Reason,
+		})
+	}
+	return err
+}
+
+// createWithAlias сохраняет ссылку под пользовательским алиасом, минуя генератор slug.
+// Если алиас занят, возвращает repository.ErrAlreadyExists.
+func (s *urlService) createWithAlias(ctx context.Context, originalURL string, opts ShortenOptions) (string, error) {
+	if err := s.validateAlias(opts.Alias); err != nil {
+		s.logger.Warn("Invalid alias", map[string]interface{}{
+			"alias": opts.Alias,
+			"error": err.Error(),
+		})
+		return "", err
+	}
+
+	link := &model.Link{
+		Slug:         opts.Alias,
+		URL:          originalURL,
+		CanonicalURL: s.canonicalURL(originalURL),
+		CreatedAt:    time.Now(),
+		ExpiresAt:    opts.ExpiresAt,
+		OwnerID:      opts.OwnerID,
+		RedirectType: opts.RedirectType,
+
+		QueryPassthrough: opts.QueryPassthrough,
+		PathPassthrough:  opts.PathPassthrough,
+		UTM:              normalizeUTM(opts.UTM),
+		UTMTemplate:      opts.UTMTemplate,
+		Rules:            normalizeRules(opts.Rules),
+		Targets:          normalizeTargets(opts.Targets),
+		StickyTargets:    opts.StickyTargets,
+	}
+	if err := s.repo.Create(ctx, link); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			s.logger.Warn("Alias already taken", map[string]interface{}{
+				"alias": opts.Alias,
+			})
+			return "", err
+		}
+		s.logger.Error("Failed to create link with alias", err, map[string]interface{}{
+			"url":   originalURL,
+			"alias": opts.Alias,
+		})
+		return "", err
+	}
+	s.forgetNotFound(ctx, opts.Alias)
+
+	s.logger.Info("Successfully shortened URL with alias", map[string]interface{}{
+		"url":   originalURL,
+		"alias": opts.Alias,
+	})
+	return opts.Alias, nil
+}
+
 // service/url_service.go
-func (s *urlService) Resolve(ctx context.Context, slug string) (string, error) {
+func (s *urlService) Resolve(ctx context.Context, slug string, v Visitor) (*Redirect, error) {
+	ctx, span := tracing.Start(ctx, "urlService.Resolve", attribute.String("link.slug", slug))
+	r, err := s.resolve(ctx, slug, v)
+	if r != nil {
+		span.SetAttributes(attribute.Int("redirect.status", r.Status))
+	}
+	tracing.End(span, spanError(err))
+	return r, err
+}
+
+func (s *urlService) resolve(ctx context.Context, slug string, v Visitor) (*Redirect, error) {
 	if slug == "" {
 		s.logger.Warn("Empty slug in resolve", nil)
-		return "", errors.New("empty slug")
+		return nil, errors.New("empty slug")
 	}
 
 	// 1. Проверяем кэш
-	url, err := s.cache.Get(ctx, slug)
+	value, err := s.cache.Get(ctx, slug)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("cache.hit", err == nil))
+	if err == nil && value == cache.NotFound {
+		s.logger.Debug("Negative cache hit", map[string]interface{}{"slug": slug})
+		return nil, repository.ErrNotFound
+	}
 	if err == nil {
-		s.logger.Info("Cache hit", map[string]interface{}{"slug": slug})
-		return url, nil
+		entry, decodeErr := decodeCachedLink(value)
+		if decodeErr == nil {
+			now := time.Now()
+			// Локальный LRU хранит запись свой TTL, а не остаток жизни ссылки, поэтому срок проверяется здесь
+			if entry.ExpiresAt != nil && !now.Before(*entry.ExpiresAt) {
+				s.logger.Warn("Slug expired", map[string]interface{}{
+					"slug":       slug,
+					"expires_at": *entry.ExpiresAt,
+				})
+				return nil, ErrLinkExpired
+			}
+			s.logger.Info("Cache hit", map[string]interface{}{"slug": slug})
+			return s.redirect(entry, v, now), nil
+		}
+		err = decodeErr
 	}
 
 	// Логируем только НЕ "cache miss" ошибки
@@ -139,19 +341,83 @@ func (s *urlService) Resolve(ctx context.Context, slug string) (string, error) {
 		})
 	}
 
-	// 2. Идём в базу
+	// 2. Идём в базу — один запрос на все одновременные промахи по этому slug
+	entry, err := s.resolveShared(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.redirect(entry, v, time.Now()), nil
+}
+
+// resolveShared загружает ссылку из базы через singleflight. Загрузка идёт в контексте,
+// отвязанном от отмены запроса: клиент, не дождавшийся ответа, не срывает её остальным.
+func (s *urlService) resolveShared(ctx context.Context, slug string) (cachedLink, error) {
+	ch := s.resolving.DoChan(slug, func() (interface{}, error) {
+		fetchCtx, cancel := s.detachedContext(ctx)
+		defer cancel()
+
+		fetchCtx, span := tracing.Start(fetchCtx, "urlService.resolveFromDB", attribute.String("link.slug", slug))
+		entry, err := s.resolveFromDB(fetchCtx, slug)
+		tracing.End(span, spanError(err))
+		return entry, err
+	})
+
+	select {
+	case res := <-ch:
+		// shared: результат загрузки, начатой другим запросом (её спан — в трассе того запроса)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("resolve.shared", res.Shared))
+		if res.Err != nil {
+			return cachedLink{}, res.Err
+		}
+		return res.Val.(cachedLink), nil
+	case <-ctx.Done():
+		return cachedLink{}, ctx.Err()
+	}
+}
+
+// detachedContext сохраняет значения ctx, но не его отмену; время ограничивается DBTimeout.
+func (s *urlService) detachedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if s.cfg.DBTimeout > 0 {
+		return context.WithTimeout(detached, s.cfg.DBTimeout)
+	}
+	return context.WithCancel(detached)
+}
+
+// resolveFromDB читает ссылку из базы, проверяет её состояние и кладёт её в кэш.
+func (s *urlService) resolveFromDB(ctx context.Context, slug string) (cachedLink, error) {
 	link, err := s.repo.GetBySlug(ctx, slug)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			s.logger.Warn("Slug not found", map[string]interface{}{"slug": slug})
-			return "", err
+			s.rememberNotFound(ctx, slug)
+			return cachedLink{}, err
+		}
+		if errors.Is(err, repository.ErrUnavailable) {
+			// Открытый breaker уже залогирован при переходе; не пишем ошибку на каждый запрос
+			return cachedLink{}, err
 		}
 		s.logger.Error("Failed to fetch slug from DB", err, nil)
-		return "", err
+		return cachedLink{}, err
+	}
+
+	if !link.IsActive() {
+		s.logger.Warn("Slug disabled", map[string]interface{}{"slug": slug})
+		return cachedLink{}, ErrLinkDisabled
 	}
 
-	// 3. Обновляем кэш (добавляем обработку ошибок записи)
-	if err := s.cache.SetNX(ctx, slug, link.URL, s.cfg.CacheTTL); err != nil {
+	now := time.Now()
+	if link.IsExpired(now) {
+		s.logger.Warn("Slug expired", map[string]interface{}{
+			"slug":       slug,
+			"expires_at": *link.ExpiresAt,
+		})
+		return cachedLink{}, ErrLinkExpired
+	}
+
+	// Обновляем кэш (добавляем обработку ошибок записи)
+	entry := newCachedLink(link)
+	if err := s.cache.SetNX(ctx, slug, entry.encode(), s.cacheTTL(link, now)); err != nil {
 		s.logger.Warn("Failed to update cache", map[string]interface{}{
 			"slug":  slug,
 			"error": err.Error(),
@@ -162,5 +428,41 @@ func (s *urlService) Resolve(ctx context.Context, slug string) (string, error) {
 		"slug": slug,
 		"url":  link.URL,
 	})
-	return link.URL, nil
+	return entry, nil
+}
+
+// cacheTTL возвращает время жизни записи в кэше: запись не должна пережить саму ссылку.
+// Нулевой CacheTTL означает хранение без ограничения по времени.
+func (s *urlService) cacheTTL(link *model.Link, now time.Time) time.Duration {
+	ttl := s.cfg.CacheTTL
+	if link.ExpiresAt == nil {
+		return ttl
+	}
+
+	untilExpiry := link.ExpiresAt.Sub(now)
+	if ttl <= 0 || untilExpiry < ttl {
+		return untilExpiry
+	}
+	return ttl
+}
+
+// rememberNotFound кладёт в кэш негативную запись, чтобы повторные запросы несуществующего
+// slug не доходили до базы. Выключено при нулевом NegativeCacheTTL.
+func (s *urlService) rememberNotFound(ctx context.Context, slug string) {
+	if s.cfg.NegativeCacheTTL <= 0 {
+		return
+	}
+	if err := s.cache.SetNX(ctx, slug, cache.NotFound, s.cfg.NegativeCacheTTL); err != nil {
+		s.logger.Warn("Failed to cache missing slug", map[string]interface{}{
+			"slug":  slug,
+			"error": err.Error(),
+		})
+	}
+}
+
+// forgetNotFound сбрасывает негативную запись только что созданного slug.
+func (s *urlService) forgetNotFound(ctx context.Context, slug string) {
+	if s.cfg.NegativeCacheTTL > 0 {
+		s.invalidateCache(ctx, slug)
+	}
 }
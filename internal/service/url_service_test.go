@@ -3,10 +3,15 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 	"unicode"
 
 	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/cache"
 	"github.com/Thoustick/SlugKiller/internal/model"
 	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/internal/service"
@@ -24,6 +29,11 @@ type testURLService struct {
 }
 
 func setupURLService() testURLService {
+	return setupURLServiceWithConfig(nil)
+}
+
+// setupURLServiceWithConfig позволяет тесту поправить конфигурацию перед созданием сервиса.
+func setupURLServiceWithConfig(tune func(cfg *config.Config)) testURLService {
 	repo := new(mocks.MockURLRepository)
 	cache := new(mocks.MockCache)
 	logger := new(mocks.MockLogger)
@@ -35,8 +45,19 @@ func setupURLService() testURLService {
 	logger.On("Debug", mock.Anything, mock.Anything).Maybe()
 	logger.On("Fatal", mock.Anything, mock.Anything, mock.Anything).Maybe()
 
-	cfg := &config.Config{CacheTTL: 0, MaxAttempts: 5, SlugLength: 10}
-	svc := service.NewURLService(repo, logger, cache, cfg, slugGen)
+	cfg := &config.Config{
+		CacheTTL:          0,
+		MaxAttempts:       5,
+		SlugLength:        10,
+		AliasMinLength:    3,
+		AliasMaxLength:    32,
+		ReservedAliases:   []string{"promo"},
+		URLTrackingParams: []string{"utm_*"},
+	}
+	if tune != nil {
+		tune(cfg)
+	}
+	svc := service.NewURLService(repo, logger, cache, cfg, slugGen, nil)
 
 	return testURLService{
 		svc:     svc,
@@ -49,7 +70,7 @@ func setupURLService() testURLService {
 
 func TestShorten_EmptyURL(t *testing.T) {
 	ts := setupURLService()
-	slug, err := ts.svc.Shorten(context.Background(), "")
+	slug, err := ts.svc.Shorten(context.Background(), "", service.ShortenOptions{})
 
 	assert.Error(t, err)
 	assert.Empty(t, slug)
@@ -59,15 +80,15 @@ func TestShorten_EmptyURL(t *testing.T) {
 
 func TestShorten_ExistingURL(t *testing.T) {
 	ts := setupURLService()
-	original := "https://example.com"
+	original := "https://example.com/"
 	expectedSlug := "abc123"
 
-	ts.repo.On("GetByOriginalURL", mock.Anything, original).Return(&model.Link{
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(&model.Link{
 		URL:  original,
 		Slug: expectedSlug,
 	}, nil)
 
-	slug, err := ts.svc.Shorten(context.Background(), original)
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSlug, slug)
@@ -76,12 +97,12 @@ func TestShorten_ExistingURL(t *testing.T) {
 
 func TestShorten_DBError(t *testing.T) {
 	ts := setupURLService()
-	original := "https://example.com"
+	original := "https://example.com/"
 	dbErr := errors.New("db down")
 
-	ts.repo.On("GetByOriginalURL", mock.Anything, original).Return(nil, dbErr)
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, dbErr)
 
-	slug, err := ts.svc.Shorten(context.Background(), original)
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
 
 	assert.Error(t, err)
 	assert.Empty(t, slug)
@@ -90,15 +111,15 @@ func TestShorten_DBError(t *testing.T) {
 
 func TestShorten_CreateNewSlug(t *testing.T) {
 	ts := setupURLService()
-	original := "https://newsite.com"
+	original := "https://newsite.com/"
 	generatedSlug := "customSlug123"
 
-	ts.repo.On("GetByOriginalURL", mock.Anything, original).Return(nil, repository.ErrNotFound)
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound)
 	ts.slugGen.On("Generate", mock.Anything).Return(generatedSlug, nil).Once()
 	ts.repo.On("GetBySlug", mock.Anything, generatedSlug).Return(nil, repository.ErrNotFound).Once()
 	ts.repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Link")).Return(nil).Once()
 
-	slug, err := ts.svc.Shorten(context.Background(), original)
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, generatedSlug, slug)
@@ -106,15 +127,34 @@ func TestShorten_CreateNewSlug(t *testing.T) {
 	ts.slugGen.AssertExpectations(t)
 }
 
+func TestShorten_OwnerScopedLink(t *testing.T) {
+	ts := setupURLService()
+	original := "https://newsite.com/"
+
+	// Ссылка анонима или другого владельца на тот же адрес не переиспользуется
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "team-a", original).Return(nil, repository.ErrNotFound).Once()
+	ts.slugGen.On("Generate", mock.Anything).Return("teamSlug", nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "teamSlug").Return(nil, repository.ErrNotFound).Once()
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Slug == "teamSlug" && l.OwnerID == "team-a"
+	})).Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{OwnerID: "team-a"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "teamSlug", slug)
+	ts.repo.AssertExpectations(t)
+}
+
 func TestShorten_CreateNewSlug_Retries(t *testing.T) {
 	ts := setupURLService()
-	original := "https://retrytest.com"
+	original := "https://retrytest.com/"
 
 	firstSlug := "slug_collision"
 	secondSlug := "slug_collision_again"
 	finalSlug := "slug_unique"
 
-	ts.repo.On("GetByOriginalURL", mock.Anything, original).Return(nil, repository.ErrNotFound)
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound)
 
 	// Эмуляция трёх попыток с коллизиями
 	ts.slugGen.On("Generate", mock.Anything).Return(firstSlug, nil).Once()
@@ -126,7 +166,7 @@ func TestShorten_CreateNewSlug_Retries(t *testing.T) {
 	ts.repo.On("GetBySlug", mock.Anything, finalSlug).Return(nil, repository.ErrNotFound).Once()
 	ts.repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
 
-	slug, err := ts.svc.Shorten(context.Background(), original)
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
 
 	assert.NoError(t, err)
 	assert.Equal(t, finalSlug, slug)
@@ -136,19 +176,126 @@ func TestShorten_CreateNewSlug_Retries(t *testing.T) {
 
 func TestShorten_GenerationFails(t *testing.T) {
 	ts := setupURLService()
-	original := "https://error-during-generation.com"
+	original := "https://error-during-generation.com/"
 	genErr := errors.New("slug generation error")
 
-	ts.repo.On("GetByOriginalURL", mock.Anything, original).Return(nil, repository.ErrNotFound)
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound)
 	ts.slugGen.On("Generate", mock.Anything).Return("", genErr).Once()
 
-	slug, err := ts.svc.Shorten(context.Background(), original)
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
 	assert.Error(t, err)
 	assert.Empty(t, slug)
 	ts.repo.AssertExpectations(t)
 	ts.slugGen.AssertExpectations(t)
 }
 
+func TestShorten_WithExpiration_SkipsDedupe(t *testing.T) {
+	ts := setupURLService()
+	original := "https://example.com/sale"
+	expiresAt := time.Now().Add(time.Hour)
+
+	ts.slugGen.On("Generate", mock.Anything).Return("sale1", nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "sale1").Return(nil, repository.ErrNotFound).Once()
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.ExpiresAt != nil && l.ExpiresAt.Equal(expiresAt)
+	})).Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{ExpiresAt: &expiresAt})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sale1", slug)
+	ts.repo.AssertNotCalled(t, "GetByCanonicalURL", mock.Anything, mock.Anything, mock.Anything)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestShorten_ExistingTemporaryLinkNotReused(t *testing.T) {
+	ts := setupURLService()
+	original := "https://example.com/"
+	expiresAt := time.Now().Add(time.Hour)
+
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(&model.Link{
+		URL:       original,
+		Slug:      "temporary",
+		ExpiresAt: &expiresAt,
+	}, nil)
+	ts.slugGen.On("Generate", mock.Anything).Return("permanent", nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "permanent").Return(nil, repository.ErrNotFound).Once()
+	ts.repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Link")).Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "permanent", slug)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestShorten_ExpirationInPast(t *testing.T) {
+	ts := setupURLService()
+	expiresAt := time.Now().Add(-time.Minute)
+
+	slug, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{ExpiresAt: &expiresAt})
+
+	assert.ErrorIs(t, err, service.ErrInvalidExpiry)
+	assert.Empty(t, slug)
+	ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestShorten_WithAlias(t *testing.T) {
+	ts := setupURLService()
+	original := "https://example.com/spring"
+
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Slug == "spring-sale" && l.URL == original
+	})).Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{Alias: "spring-sale"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "spring-sale", slug)
+	ts.repo.AssertExpectations(t)
+	ts.repo.AssertNotCalled(t, "GetByCanonicalURL", mock.Anything, mock.Anything, mock.Anything)
+	ts.slugGen.AssertNotCalled(t, "Generate", mock.Anything)
+}
+
+func TestShorten_AliasTaken(t *testing.T) {
+	ts := setupURLService()
+
+	ts.repo.On("Create", mock.Anything, mock.AnythingOfType("*model.Link")).Return(repository.ErrAlreadyExists).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{Alias: "taken"})
+
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	assert.Empty(t, slug)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestShorten_InvalidAlias(t *testing.T) {
+	cases := []struct {
+		alias   string
+		wantErr error
+	}{
+		{alias: "ab", wantErr: service.ErrInvalidAlias},
+		{alias: "this-alias-is-definitely-way-too-long", wantErr: service.ErrInvalidAlias},
+		{alias: "with space", wantErr: service.ErrInvalidAlias},
+		{alias: "привет", wantErr: service.ErrInvalidAlias},
+		{alias: "shorten", wantErr: service.ErrAliasReserved},
+		{alias: "API", wantErr: service.ErrAliasReserved},
+		{alias: "Promo", wantErr: service.ErrAliasReserved},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.alias, func(t *testing.T) {
+			ts := setupURLService()
+
+			slug, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{Alias: tc.alias})
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Empty(t, slug)
+			ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func setupResolveService() (service.URLService, *mocks.MockURLRepository, *mocks.MockCache, *mocks.MockLogger) {
 	repo := new(mocks.MockURLRepository)
 	cache := new(mocks.MockCache)
@@ -162,15 +309,15 @@ func setupResolveService() (service.URLService, *mocks.MockURLRepository, *mocks
 	logger.On("Fatal", mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 	cfg := &config.Config{CacheTTL: 0}
-	svc := service.NewURLService(repo, logger, cache, cfg, slugGen)
+	svc := service.NewURLService(repo, logger, cache, cfg, slugGen, nil)
 	return svc, repo, cache, logger
 }
 
 func TestResolve_EmptySlug(t *testing.T) {
 	svc, _, _, _ := setupResolveService()
-	url, err := svc.Resolve(context.Background(), "")
+	r, err := svc.Resolve(context.Background(), "", service.Visitor{})
 	assert.Error(t, err)
-	assert.Empty(t, url)
+	assert.Nil(t, r)
 }
 
 func TestResolve_CacheHit(t *testing.T) {
@@ -180,10 +327,10 @@ func TestResolve_CacheHit(t *testing.T) {
 
 	cache.On("Get", mock.Anything, slug).Return(originalURL, nil)
 
-	url, err := svc.Resolve(context.Background(), slug)
+	r, err := svc.Resolve(context.Background(), slug, service.Visitor{})
 
 	assert.NoError(t, err)
-	assert.Equal(t, originalURL, url)
+	assert.Equal(t, originalURL, r.URL)
 	cache.AssertExpectations(t)
 }
 
@@ -194,12 +341,12 @@ func TestResolve_CacheMiss_DBHit(t *testing.T) {
 
 	cache.On("Get", mock.Anything, slug).Return("", errors.New("cache miss"))
 	repo.On("GetBySlug", mock.Anything, slug).Return(&model.Link{Slug: slug, URL: originalURL}, nil)
-	cache.On("Set", mock.Anything, slug, originalURL, mock.Anything).Return(nil)
+	cache.On("SetNX", mock.Anything, slug, originalURL, mock.Anything).Return(nil)
 
-	url, err := svc.Resolve(context.Background(), slug)
+	r, err := svc.Resolve(context.Background(), slug, service.Visitor{})
 
 	assert.NoError(t, err)
-	assert.Equal(t, originalURL, url)
+	assert.Equal(t, originalURL, r.URL)
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
@@ -211,10 +358,10 @@ func TestResolve_SlugNotFound(t *testing.T) {
 	cache.On("Get", mock.Anything, slug).Return("", errors.New("cache miss"))
 	repo.On("GetBySlug", mock.Anything, slug).Return(nil, repository.ErrNotFound)
 
-	url, err := svc.Resolve(context.Background(), slug)
+	r, err := svc.Resolve(context.Background(), slug, service.Visitor{})
 
 	assert.Error(t, err)
-	assert.Empty(t, url)
+	assert.Nil(t, r)
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
@@ -227,10 +374,10 @@ func TestResolve_DBError(t *testing.T) {
 	cache.On("Get", mock.Anything, slug).Return("", errors.New("cache miss"))
 	repo.On("GetBySlug", mock.Anything, slug).Return(nil, dbErr)
 
-	url, err := svc.Resolve(context.Background(), slug)
+	r, err := svc.Resolve(context.Background(), slug, service.Visitor{})
 
 	assert.Error(t, err)
-	assert.Empty(t, url)
+	assert.Nil(t, r)
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
@@ -247,3 +394,282 @@ func TestDefaultSlugGenerator_Generate(t *testing.T) {
 		assert.True(t, isValid, "unexpected rune in slug: %q", r)
 	}
 }
+
+func TestResolve_Expired(t *testing.T) {
+	svc, repo, cache, _ := setupResolveService()
+	slug := "old"
+	expiredAt := time.Now().Add(-time.Second)
+
+	cache.On("Get", mock.Anything, slug).Return("", errors.New("cache miss"))
+	repo.On("GetBySlug", mock.Anything, slug).Return(&model.Link{
+		Slug:      slug,
+		URL:       "https://example.com",
+		ExpiresAt: &expiredAt,
+	}, nil)
+
+	r, err := svc.Resolve(context.Background(), slug, service.Visitor{})
+
+	assert.ErrorIs(t, err, service.ErrLinkExpired)
+	assert.Nil(t, r)
+	cache.AssertNotCalled(t, "SetNX", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolve_ExpiredCacheHit(t *testing.T) {
+	svc, repo, cache, _ := setupResolveService()
+	expiredAt := time.Now().Add(-time.Second).UTC().Format(time.RFC3339Nano)
+
+	// Запись пережила ссылку, например в локальном LRU
+	cache.On("Get", mock.Anything, "old").Return(`{"u":"https://example.com","e":"`+expiredAt+`"}`, nil).Once()
+
+	r, err := svc.Resolve(context.Background(), "old", service.Visitor{})
+
+	assert.ErrorIs(t, err, service.ErrLinkExpired)
+	assert.Nil(t, r)
+	repo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+}
+
+func TestResolve_CacheTTLBoundedByExpiry(t *testing.T) {
+	repo := new(mocks.MockURLRepository)
+	cache := new(mocks.MockCache)
+	logger := new(mocks.MockLogger)
+	logger.On("Info", mock.Anything, mock.Anything).Maybe()
+	logger.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	cfg := &config.Config{CacheTTL: 24 * time.Hour}
+	svc := service.NewURLService(repo, logger, cache, cfg, new(mocks.MockSlugGenerator), nil)
+
+	slug := "soon"
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	cache.On("Get", mock.Anything, slug).Return("", errors.New("cache miss"))
+	repo.On("GetBySlug", mock.Anything, slug).Return(&model.Link{
+		Slug:      slug,
+		URL:       "https://example.com",
+		ExpiresAt: &expiresAt,
+	}, nil)
+	cache.On("SetNX", mock.Anything, slug, mock.MatchedBy(func(v string) bool {
+		// Ссылка со сроком жизни кэшируется вместе с ним
+		return strings.HasPrefix(v, `{"u":"https://example.com"`)
+	}), mock.MatchedBy(func(ttl time.Duration) bool {
+		return ttl > 0 && ttl <= 10*time.Minute
+	})).Return(nil).Once()
+
+	r, err := svc.Resolve(context.Background(), slug, service.Visitor{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", r.URL)
+	cache.AssertExpectations(t)
+}
+
+func TestShorten_RejectedByPolicy(t *testing.T) {
+	ts := setupURLService()
+	policy := new(mocks.MockURLPolicy)
+	svc := service.NewURLService(ts.repo, ts.logger, ts.cache, &config.Config{MaxAttempts: 5}, ts.slugGen, policy)
+	violation := &service.URLPolicyError{Reason: service.URLPolicyPrivateAddress, Detail: "private"}
+
+	policy.On("Check", mock.Anything, "http://127.0.0.1").Return(violation).Once()
+
+	_, err := svc.Shorten(context.Background(), "http://127.0.0.1", service.ShortenOptions{})
+
+	var got *service.URLPolicyError
+	assert.ErrorAs(t, err, &got)
+	assert.Equal(t, service.URLPolicyPrivateAddress, got.Reason)
+	ts.repo.AssertNotCalled(t, "GetByCanonicalURL", mock.Anything, mock.Anything, mock.Anything)
+	ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestShorten_DedupesByCanonicalURL(t *testing.T) {
+	ts := setupURLService()
+	original := "HTTPS://Example.com:443/a?b=1&a=2&utm_source=tg"
+	canonical := "https://example.com/a?a=2&b=1"
+
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", canonical).Return(nil, repository.ErrNotFound).Once()
+	ts.slugGen.On("Generate", mock.Anything).Return("abc123", nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "abc123").Return(nil, repository.ErrNotFound).Once()
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		// Редиректим на исходный адрес, канонический нужен только для поиска дублей
+		return l.URL == original && l.CanonicalURL == canonical
+	})).Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", slug)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestResolve_ConcurrentMissesShareOneLookup(t *testing.T) {
+	ts := setupURLService()
+	const n = 50
+
+	var misses sync.WaitGroup
+	misses.Add(n)
+	release := make(chan struct{})
+
+	ts.cache.On("Get", mock.Anything, "viral").
+		Run(func(mock.Arguments) { misses.Done() }).
+		Return("", cache.ErrCacheMiss).Times(n)
+	ts.repo.On("GetBySlug", mock.Anything, "viral").
+		Run(func(mock.Arguments) { <-release }).
+		Return(&model.Link{Slug: "viral", URL: "https://viral.example/"}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "viral", "https://viral.example/", time.Duration(0)).Return(nil).Once()
+
+	var wg sync.WaitGroup
+	results := make([]*service.Redirect, n)
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = ts.svc.Resolve(context.Background(), "viral", service.Visitor{})
+		}()
+	}
+
+	// Все запросы промахнулись мимо кэша; даём им встать в очередь за первым и отпускаем базу
+	misses.Wait()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "https://viral.example/", results[i].URL)
+	}
+	ts.repo.AssertNumberOfCalls(t, "GetBySlug", 1)
+	ts.cache.AssertNumberOfCalls(t, "SetNX", 1)
+}
+
+func TestResolve_CancelledWaiterDoesNotCancelLookup(t *testing.T) {
+	ts := setupURLService()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	missed := make(chan struct{}, 2)
+
+	ts.cache.On("Get", mock.Anything, "abc").
+		Run(func(mock.Arguments) { missed <- struct{}{} }).
+		Return("", cache.ErrCacheMiss)
+	ts.repo.On("GetBySlug", mock.Anything, "abc").
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-release
+			// Отмена запроса первого клиента не доходит до общей загрузки
+			assert.NoError(t, args.Get(0).(context.Context).Err())
+		}).
+		Return(&model.Link{Slug: "abc", URL: "https://example.com/"}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "abc", "https://example.com/", time.Duration(0)).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := ts.svc.Resolve(ctx, "abc", service.Visitor{})
+		cancelled <- err
+	}()
+	<-started
+
+	patient := make(chan string, 1)
+	go func() {
+		r, err := ts.svc.Resolve(context.Background(), "abc", service.Visitor{})
+		if err != nil {
+			patient <- err.Error()
+			return
+		}
+		patient <- r.URL
+	}()
+
+	<-missed
+	<-missed
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-cancelled, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "https://example.com/", <-patient)
+	ts.repo.AssertExpectations(t)
+	ts.cache.AssertExpectations(t)
+}
+
+func withNegativeCache(cfg *config.Config) {
+	cfg.NegativeCacheTTL = time.Minute
+}
+
+func TestResolve_NotFoundIsCachedNegatively(t *testing.T) {
+	ts := setupURLServiceWithConfig(withNegativeCache)
+
+	ts.cache.On("Get", mock.Anything, "nope").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "nope").Return(nil, repository.ErrNotFound).Once()
+	ts.cache.On("SetNX", mock.Anything, "nope", cache.NotFound, time.Minute).Return(nil).Once()
+
+	_, err := ts.svc.Resolve(context.Background(), "nope", service.Visitor{})
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	ts.repo.AssertExpectations(t)
+	ts.cache.AssertExpectations(t)
+}
+
+func TestResolve_NegativeCacheHitSkipsDB(t *testing.T) {
+	ts := setupURLServiceWithConfig(withNegativeCache)
+
+	ts.cache.On("Get", mock.Anything, "nope").Return(cache.NotFound, nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "nope", service.Visitor{})
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Nil(t, r)
+	ts.repo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything)
+}
+
+func TestResolve_NegativeCacheDisabled(t *testing.T) {
+	ts := setupURLService()
+
+	ts.cache.On("Get", mock.Anything, "nope").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "nope").Return(nil, repository.ErrNotFound).Once()
+
+	_, err := ts.svc.Resolve(context.Background(), "nope", service.Visitor{})
+
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	ts.cache.AssertNotCalled(t, "SetNX", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestShorten_CreateClearsNegativeCache(t *testing.T) {
+	ts := setupURLServiceWithConfig(withNegativeCache)
+	original := "https://example.com/"
+
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.Slug == "my-promo"
+	})).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "my-promo").Return(nil).Once()
+
+	slug, err := ts.svc.Shorten(context.Background(), original, service.ShortenOptions{Alias: "my-promo"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "my-promo", slug)
+	ts.cache.AssertExpectations(t)
+
+	ts = setupURLServiceWithConfig(withNegativeCache)
+	ts.repo.On("GetByCanonicalURL", mock.Anything, "", original).Return(nil, repository.ErrNotFound).Once()
+	ts.slugGen.On("Generate", mock.Anything).Return("abc123", nil).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "abc123").Return(nil, repository.ErrNotFound).Once()
+	ts.repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "abc123").Return(nil).Once()
+
+	slug, err = ts.svc.Shorten(context.Background(), original, service.ShortenOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", slug)
+	ts.cache.AssertExpectations(t)
+}
+
+func TestIsRejected(t *testing.T) {
+	for _, err := range []error{
+		service.ErrInvalidAlias,
+		service.ErrInvalidTargets,
+		fmt.Errorf("rule 2: %w", service.ErrInvalidRule),
+		&service.URLPolicyError{Reason: service.URLPolicyDomainBlocked},
+	} {
+		assert.True(t, service.IsRejected(err), err.Error())
+	}
+	for _, err := range []error{nil, repository.ErrUnavailable, repository.ErrNotFound, errors.New("db down")} {
+		assert.False(t, service.IsRejected(err))
+	}
+}
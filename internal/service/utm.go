@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
+
+// LoadUTMTemplates читает шаблоны UTM-меток из JSON-файла вида
+// {"newsletter": {"source": "newsletter", "medium": "email"}}. Пустой path — шаблонов нет.
+func LoadUTMTemplates(path string) (map[string]model.UTM, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read UTM templates: %w", err)
+	}
+	var templates map[string]model.UTM
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("parse UTM templates %s: %w", path, err)
+	}
+	for name, utm := range templates {
+		if utm.IsZero() {
+			return nil, fmt.Errorf("UTM template %q has no parameters", name)
+		}
+	}
+	return templates, nil
+}
+
+// validateUTMTemplate проверяет, что шаблон с таким именем есть в конфигурации; пустое имя — без шаблона.
+func (s *urlService) validateUTMTemplate(name string) error {
+	if name == "" {
+		return nil
+	}
+	if _, ok := s.cfg.UTMTemplates[name]; !ok {
+		return ErrUnknownUTMTemplate
+	}
+	return nil
+}
+
+// utm возвращает метки, которые дописываются при переходе: метки шаблона, поверх которых
+// действуют метки самой ссылки. Шаблон, удалённый из конфигурации, просто не применяется.
+func (s *urlService) utm(e cachedLink) model.UTM {
+	utm := s.cfg.UTMTemplates[e.UTMTemplate]
+	if e.UTM != nil {
+		utm = utm.Override(*e.UTM)
+	}
+	return utm
+}
+
+// normalizeUTM возвращает nil вместо набора без меток, чтобы в хранилище не попадал пустой объект.
+func normalizeUTM(utm *model.UTM) *model.UTM {
+	if utm == nil || utm.IsZero() {
+		return nil
+	}
+	return utm
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+func TestLoadUTMTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("шаблоны из файла", func(t *testing.T) {
+		path := filepath.Join(dir, "utm.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"newsletter": {"source": "newsletter", "medium": "email"}}`), 0o600))
+
+		templates, err := service.LoadUTMTemplates(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, model.UTM{Source: "newsletter", Medium: "email"}, templates["newsletter"])
+	})
+
+	t.Run("без файла шаблонов нет", func(t *testing.T) {
+		templates, err := service.LoadUTMTemplates("")
+		assert.NoError(t, err)
+		assert.Empty(t, templates)
+	})
+
+	t.Run("пустой шаблон — ошибка", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"blank": {}}`), 0o600))
+
+		_, err := service.LoadUTMTemplates(path)
+		assert.ErrorContains(t, err, "blank")
+	})
+
+	t.Run("файл не найден", func(t *testing.T) {
+		_, err := service.LoadUTMTemplates(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+	})
+}
+
+func withUTMTemplates(cfg *config.Config) {
+	cfg.UTMTemplates = map[string]model.UTM{
+		"newsletter": {Source: "newsletter", Medium: "email", Campaign: "weekly"},
+	}
+}
+
+func TestResolve_AppliesUTMTemplateWithLinkOverrides(t *testing.T) {
+	ts := setupURLServiceWithConfig(withUTMTemplates)
+
+	ts.cache.On("Get", mock.Anything, "promo").Return("", cache.ErrCacheMiss).Once()
+	ts.repo.On("GetBySlug", mock.Anything, "promo").Return(&model.Link{
+		Slug:        "promo",
+		URL:         "https://example.com/sale?utm_source=old&id=7",
+		UTM:         &model.UTM{Campaign: "spring"},
+		UTMTemplate: "newsletter",
+	}, nil).Once()
+	ts.cache.On("SetNX", mock.Anything, "promo", mock.Anything, mock.Anything).Return(nil).Once()
+
+	r, err := ts.svc.Resolve(context.Background(), "promo", service.Visitor{})
+
+	require.NoError(t, err)
+	assert.Equal(t, model.UTM{Source: "newsletter", Medium: "email", Campaign: "spring"}, r.UTM)
+	assert.Equal(t,
+		"https://example.com/sale?id=7&utm_source=newsletter&utm_medium=email&utm_campaign=spring",
+		r.Location("", nil))
+}
+
+func TestShorten_UnknownUTMTemplate(t *testing.T) {
+	ts := setupURLServiceWithConfig(withUTMTemplates)
+
+	_, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{UTMTemplate: "podcast"})
+
+	assert.ErrorIs(t, err, service.ErrUnknownUTMTemplate)
+	ts.repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestShorten_StoresUTM(t *testing.T) {
+	ts := setupURLServiceWithConfig(withUTMTemplates)
+
+	ts.repo.On("Create", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		// Адрес назначения хранится без меток
+		return l.URL == "https://example.com" && l.UTMTemplate == "newsletter" && l.UTM == nil
+	})).Return(nil).Once()
+
+	_, err := ts.svc.Shorten(context.Background(), "https://example.com", service.ShortenOptions{
+		Alias:       "news",
+		UTM:         &model.UTM{},
+		UTMTemplate: "newsletter",
+	})
+
+	assert.NoError(t, err)
+	ts.repo.AssertExpectations(t)
+}
+
+func TestUpdateLink_UTM(t *testing.T) {
+	ts := setupURLServiceWithConfig(withUTMTemplates)
+	stored := &model.Link{Slug: "abc", URL: "https://example.com", UTM: &model.UTM{Source: "tg"}}
+	utm := model.UTM{Source: "vk", Campaign: "autumn"}
+
+	ts.repo.On("GetBySlug", mock.Anything, "abc").Return(stored, nil).Once()
+	ts.repo.On("Update", mock.Anything, mock.MatchedBy(func(l *model.Link) bool {
+		return l.URL == "https://example.com" && *l.UTM == utm
+	})).Return(nil).Once()
+	ts.cache.On("Delete", mock.Anything, "abc").Return(nil).Once()
+
+	link, err := ts.svc.UpdateLink(context.Background(), "", "abc", service.LinkUpdate{UTM: &utm})
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc", link.Slug, "slug does not change when UTM is edited")
+	ts.repo.AssertExpectations(t)
+}
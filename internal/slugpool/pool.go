@@ -0,0 +1,243 @@
+package slugpool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// Pool раздаёт заранее подготовленные свободные slug. Фоновый воркер генерирует
+// кандидатов, складывает их в хранилище пула (которое отбрасывает занятые) и
+// забирает оттуда пачку в локальный буфер. Generate берёт slug из буфера без
+// обращения к базе; если буфер опустел, slug забирается из хранилища напрямую.
+//
+// Slug, выданный пулом, не принадлежит ни одной ссылке и не будет выдан повторно,
+// поэтому сервису не нужно проверять его занятость.
+type Pool struct {
+	gen      service.SlugGenerator
+	store    repository.SlugPoolStore
+	buffer   chan string
+	lowWater int
+	timeout  time.Duration
+	logger   logger.Logger
+
+	refillCh chan struct{}
+	misses   atomic.Uint64
+}
+
+var (
+	_ service.CollisionFreeSlugGenerator = (*Pool)(nil)
+
+	errPoolExhausted = errors.New("slug pool is exhausted")
+)
+
+// Значения по умолчанию для незаполненных полей конфигурации
+const (
+	defaultStoreTimeout = 5 * time.Second
+	checkInterval       = time.Second
+	directBatchSize     = 16 // сколько кандидатов готовить, если slug нужен прямо сейчас
+)
+
+// New создаёт пул поверх генератора gen. Стратегия hash для пула не подходит:
+// ей нужен URL создаваемой ссылки, а пул готовит slug заранее.
+func New(gen service.SlugGenerator, store repository.SlugPoolStore, cfg *config.Config, log logger.Logger) (*Pool, error) {
+	if cfg.SlugStrategy == service.SlugStrategyHash {
+		return nil, errors.New("slug pool cannot be used with the hash slug strategy")
+	}
+	if cfg.SlugPoolSize <= 0 {
+		return nil, errors.New("slug pool size must be positive")
+	}
+
+	lowWater := cfg.SlugPoolLowWater
+	if lowWater <= 0 || lowWater > cfg.SlugPoolSize {
+		lowWater = max(cfg.SlugPoolSize/4, 1)
+	}
+
+	timeout := cfg.DBTimeout
+	if timeout <= 0 {
+		timeout = defaultStoreTimeout
+	}
+
+	return &Pool{
+		gen:      gen,
+		store:    store,
+		buffer:   make(chan string, cfg.SlugPoolSize),
+		lowWater: lowWater,
+		timeout:  timeout,
+		logger:   log,
+		refillCh: make(chan struct{}, 1),
+	}, nil
+}
+
+// Generate выдаёт следующий slug из буфера.
+func (p *Pool) Generate(ctx context.Context) (string, error) {
+	select {
+	case slug := <-p.buffer:
+		if len(p.buffer) < p.lowWater {
+			p.wake()
+		}
+		return slug, nil
+	default:
+	}
+
+	p.misses.Add(1)
+	p.wake()
+	return p.takeDirect(ctx)
+}
+
+// CollisionFree: slug из пула свободны и выдаются один раз.
+func (p *Pool) CollisionFree() bool { return true }
+
+// Depth возвращает число slug в локальном буфере.
+func (p *Pool) Depth() int {
+	return len(p.buffer)
+}
+
+// Misses возвращает, сколько раз буфер оказывался пуст и slug брался из хранилища напрямую.
+func (p *Pool) Misses() uint64 {
+	return p.misses.Load()
+}
+
+// Run пополняет буфер, когда он опускается ниже порога, до отмены ctx.
+// При остановке невыданные slug возвращаются в хранилище пула.
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	p.refill(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			p.release()
+			return
+		case <-p.refillCh:
+		case <-ticker.C:
+		}
+
+		if len(p.buffer) < p.lowWater {
+			p.refill(ctx)
+		}
+	}
+}
+
+func (p *Pool) wake() {
+	select {
+	case p.refillCh <- struct{}{}:
+	default:
+	}
+}
+
+// refill дополняет буфер до ёмкости.
+func (p *Pool) refill(ctx context.Context) {
+	need := cap(p.buffer) - len(p.buffer)
+	if need <= 0 {
+		return
+	}
+
+	slugs, err := p.take(ctx, need)
+	if err != nil {
+		p.logger.Error("Failed to refill slug pool", err, map[string]interface{}{
+			"depth": len(p.buffer),
+		})
+	}
+
+	var rest []string
+	for i, slug := range slugs {
+		select {
+		case p.buffer <- slug:
+			continue
+		default:
+		}
+		rest = slugs[i:]
+		break
+	}
+	if len(rest) > 0 {
+		p.giveBack(rest)
+	}
+
+	p.logger.Debug("Slug pool refilled", map[string]interface{}{
+		"added": len(slugs) - len(rest),
+		"depth": len(p.buffer),
+	})
+}
+
+// takeDirect забирает один slug из хранилища, минуя буфер.
+func (p *Pool) takeDirect(ctx context.Context) (string, error) {
+	slugs, err := p.take(ctx, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(slugs) == 0 {
+		return "", errPoolExhausted
+	}
+	return slugs[0], nil
+}
+
+// take забирает n slug из хранилища, при нехватке предварительно пополнив его новыми кандидатами.
+func (p *Pool) take(ctx context.Context, n int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	slugs, err := p.store.TakePooledSlugs(ctx, n)
+	if err != nil || len(slugs) == n {
+		return slugs, err
+	}
+
+	missing := n - len(slugs)
+	if err := p.generate(ctx, max(missing, directBatchSize)); err != nil {
+		return slugs, err
+	}
+
+	more, err := p.store.TakePooledSlugs(ctx, missing)
+	return append(slugs, more...), err
+}
+
+// generate создаёт n кандидатов и складывает их в хранилище пула.
+func (p *Pool) generate(ctx context.Context, n int) error {
+	candidates := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		slug, err := p.gen.Generate(ctx)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, slug)
+	}
+
+	_, err := p.store.AddPooledSlugs(ctx, candidates)
+	return err
+}
+
+// release возвращает содержимое буфера в хранилище, чтобы slug не пропали при остановке.
+func (p *Pool) release() {
+	var slugs []string
+	for {
+		select {
+		case slug := <-p.buffer:
+			slugs = append(slugs, slug)
+		default:
+			p.giveBack(slugs)
+			return
+		}
+	}
+}
+
+func (p *Pool) giveBack(slugs []string) {
+	if len(slugs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	if _, err := p.store.AddPooledSlugs(ctx, slugs); err != nil {
+		p.logger.Error("Failed to return slugs to pool", err, map[string]interface{}{
+			"count": len(slugs),
+		})
+	}
+}
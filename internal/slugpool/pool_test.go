@@ -0,0 +1,85 @@
+package slugpool_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/slugpool"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func newLogger() *mocks.MockLogger {
+	log := new(mocks.MockLogger)
+	log.On("Debug", mock.Anything, mock.Anything).Maybe()
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return log
+}
+
+func TestPool_RefillsToCapacity(t *testing.T) {
+	repo := mem.NewRepo(newLogger())
+	cfg := &config.Config{SlugPoolSize: 50, SlugPoolLowWater: 10}
+	pool, err := slugpool.New(service.NewSlugGenerator(8), repo, cfg, newLogger())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return pool.Depth() == 50 }, time.Second, 5*time.Millisecond)
+
+	// Опускаемся ниже порога — воркер должен пополнить буфер
+	seen := make(map[string]struct{})
+	for i := 0; i < 45; i++ {
+		slug, err := pool.Generate(context.Background())
+		require.NoError(t, err)
+		require.NotContains(t, seen, slug)
+		seen[slug] = struct{}{}
+	}
+	assert.Eventually(t, func() bool { return pool.Depth() == 50 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, pool.Misses())
+
+	cancel()
+	<-done
+
+	// Невыданные slug вернулись в хранилище
+	assert.Zero(t, pool.Depth())
+	left, err := repo.TakePooledSlugs(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Len(t, left, 50)
+}
+
+func TestPool_EmptyBufferTakesFromStore(t *testing.T) {
+	repo := mem.NewRepo(newLogger())
+	cfg := &config.Config{SlugPoolSize: 10}
+	pool, err := slugpool.New(service.NewSequentialSlugGenerator(repo), repo, cfg, newLogger())
+	require.NoError(t, err)
+
+	// Первый кандидат счётчика уже занят ссылкой — пул его пропустит
+	require.NoError(t, repo.Create(context.Background(), &model.Link{Slug: "1", URL: "https://example.com"}))
+
+	slug, err := pool.Generate(context.Background())
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "1", slug)
+	assert.Equal(t, uint64(1), pool.Misses())
+}
+
+func TestNew_RejectsHashStrategy(t *testing.T) {
+	cfg := &config.Config{SlugPoolSize: 10, SlugStrategy: service.SlugStrategyHash}
+
+	_, err := slugpool.New(service.NewHashSlugGenerator(8), mem.NewRepo(newLogger()), cfg, newLogger())
+
+	assert.Error(t, err)
+}
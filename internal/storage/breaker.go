@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// breakerRepo пропускает обращения к хранилищу через circuit breaker. Пока breaker открыт,
+// вызовы сразу завершаются repository.ErrUnavailable, не дожидаясь таймаутов базы.
+type breakerRepo struct {
+	next    repository.URLRepository
+	breaker *breaker.Breaker
+}
+
+var _ repository.URLRepository = (*breakerRepo)(nil)
+
+// WithBreaker оборачивает репозиторий ссылок circuit breaker.
+func WithBreaker(next repository.URLRepository, b *breaker.Breaker) repository.URLRepository {
+	return &breakerRepo{next: next, breaker: b}
+}
+
+func (r *breakerRepo) GetBySlug(ctx context.Context, slug string) (*model.Link, error) {
+	if err := r.breaker.Allow(); err != nil {
+		return nil, repository.ErrUnavailable
+	}
+	link, err := r.next.GetBySlug(ctx, slug)
+	return link, r.record(err)
+}
+
+func (r *breakerRepo) GetByCanonicalURL(ctx context.Context, ownerID, key string) (*model.Link, error) {
+	if err := r.breaker.Allow(); err != nil {
+		return nil, repository.ErrUnavailable
+	}
+	link, err := r.next.GetByCanonicalURL(ctx, ownerID, key)
+	return link, r.record(err)
+}
+
+func (r *breakerRepo) Create(ctx context.Context, link *model.Link) error {
+	if err := r.breaker.Allow(); err != nil {
+		return repository.ErrUnavailable
+	}
+	return r.record(r.next.Create(ctx, link))
+}
+
+func (r *breakerRepo) Update(ctx context.Context, link *model.Link) error {
+	if err := r.breaker.Allow(); err != nil {
+		return repository.ErrUnavailable
+	}
+	return r.record(r.next.Update(ctx, link))
+}
+
+func (r *breakerRepo) Delete(ctx context.Context, slug string) error {
+	if err := r.breaker.Allow(); err != nil {
+		return repository.ErrUnavailable
+	}
+	return r.record(r.next.Delete(ctx, slug))
+}
+
+func (r *breakerRepo) record(err error) error {
+	return record(r.breaker, err)
+}
+
+// breakerKeys проверяет API-ключи через тот же breaker, что и ссылки: база одна, и пока она
+// недоступна, запрос с ключом сразу получает 503, а не ждёт таймаута подключения.
+type breakerKeys struct {
+	next    repository.APIKeyStore
+	breaker *breaker.Breaker
+}
+
+var _ repository.APIKeyStore = (*breakerKeys)(nil)
+
+// WithBreakerKeys оборачивает хранилище API-ключей circuit breaker.
+func WithBreakerKeys(next repository.APIKeyStore, b *breaker.Breaker) repository.APIKeyStore {
+	return &breakerKeys{next: next, breaker: b}
+}
+
+func (k *breakerKeys) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	if err := k.breaker.Allow(); err != nil {
+		return repository.ErrUnavailable
+	}
+	return record(k.breaker, k.next.CreateAPIKey(ctx, key))
+}
+
+func (k *breakerKeys) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	if err := k.breaker.Allow(); err != nil {
+		return nil, repository.ErrUnavailable
+	}
+	key, err := k.next.GetAPIKeyByHash(ctx, hash)
+	return key, record(k.breaker, err)
+}
+
+// breakerStats читает статистику через breaker базы: пока база недоступна, запрос статистики
+// сразу получает repository.ErrUnavailable.
+type breakerStats struct {
+	next    repository.StatsReader
+	breaker *breaker.Breaker
+}
+
+var _ repository.StatsReader = (*breakerStats)(nil)
+
+// WithBreakerStats оборачивает чтение статистики circuit breaker.
+func WithBreakerStats(next repository.StatsReader, b *breaker.Breaker) repository.StatsReader {
+	return &breakerStats{next: next, breaker: b}
+}
+
+func (s *breakerStats) LinkStats(ctx context.Context, slug string, q model.StatsQuery) (*model.LinkStats, error) {
+	if err := s.breaker.Allow(); err != nil {
+		return nil, repository.ErrUnavailable
+	}
+	stats, err := s.next.LinkStats(ctx, slug, q)
+	return stats, record(s.breaker, err)
+}
+
+// record передаёт исход вызова breaker и возвращает ошибку без изменений. «Нет записи» и
+// «уже существует» — штатные ответы базы, а отмена запроса клиентом о её здоровье не говорит.
+func record(b *breaker.Breaker, err error) error {
+	switch {
+	case err == nil, errors.Is(err, repository.ErrNotFound), errors.Is(err, repository.ErrAlreadyExists):
+		b.Success()
+	case errors.Is(err, context.Canceled):
+		b.Release()
+	default:
+		b.Failure(err)
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/breaker"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/storage"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+func TestWithBreaker_FailsFastWhenOpen(t *testing.T) {
+	log := logger.InitLogger(&config.Config{LogLevel: "error"})
+	b := breaker.New("storage", breaker.Settings{Threshold: 2, CoolDown: time.Minute}, log)
+	next := new(mocks.MockURLRepository)
+	repo := storage.WithBreaker(next, b)
+	ctx := context.Background()
+	timeout := errors.New("timeout: context deadline exceeded")
+
+	// «Нет записи» — штатный ответ и breaker не открывает
+	next.On("GetBySlug", mock.Anything, "missing").Return(nil, repository.ErrNotFound)
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetBySlug(ctx, "missing")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	}
+	assert.Equal(t, breaker.Closed, b.State())
+
+	next.On("GetBySlug", mock.Anything, "abc").Return(nil, timeout).Twice()
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetBySlug(ctx, "abc")
+		assert.ErrorIs(t, err, timeout)
+	}
+	assert.Equal(t, breaker.Open, b.State())
+
+	// Открытый breaker отвечает сразу, база не опрашивается
+	_, err := repo.GetBySlug(ctx, "abc")
+	assert.ErrorIs(t, err, repository.ErrUnavailable)
+	assert.ErrorIs(t, repo.Delete(ctx, "abc"), repository.ErrUnavailable)
+	next.AssertNumberOfCalls(t, "GetBySlug", 5)
+	next.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestWithBreakerKeys_SharesBreakerWithLinks(t *testing.T) {
+	log := logger.InitLogger(&config.Config{LogLevel: "error"})
+	b := breaker.New("storage", breaker.Settings{Threshold: 1, CoolDown: time.Minute}, log)
+	links := new(mocks.MockURLRepository)
+	store := new(mocks.MockStore)
+	repo := storage.WithBreaker(links, b)
+	keys := storage.WithBreakerKeys(store, b)
+	ctx := context.Background()
+
+	// Неизвестный ключ — штатный ответ
+	store.On("GetAPIKeyByHash", mock.Anything, "unknown").Return(nil, repository.ErrNotFound).Once()
+	_, err := keys.GetAPIKeyByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.Equal(t, breaker.Closed, b.State())
+
+	// Сбой базы на ссылках сразу отключает и проверку ключей
+	links.On("GetBySlug", mock.Anything, "abc").Return(nil, errors.New("connection refused")).Once()
+	_, _ = repo.GetBySlug(ctx, "abc")
+	_, err = keys.GetAPIKeyByHash(ctx, "hash")
+	assert.ErrorIs(t, err, repository.ErrUnavailable)
+	store.AssertNumberOfCalls(t, "GetAPIKeyByHash", 1)
+}
+
+func TestWithBreakerStats_FailsFastWhenOpen(t *testing.T) {
+	log := logger.InitLogger(&config.Config{LogLevel: "error"})
+	b := breaker.New("storage", breaker.Settings{Threshold: 1, CoolDown: time.Minute}, log)
+	store := new(mocks.MockStore)
+	stats := storage.WithBreakerStats(store, b)
+	ctx := context.Background()
+	q := model.StatsQuery{Limit: 10}
+
+	store.On("LinkStats", mock.Anything, "abc", q).Return(nil, errors.New("connection refused")).Once()
+	_, err := stats.LinkStats(ctx, "abc", q)
+	assert.Error(t, err)
+	assert.Equal(t, breaker.Open, b.State())
+
+	// Пока breaker открыт, статистика не запрашивается
+	_, err = stats.LinkStats(ctx, "abc", q)
+	assert.ErrorIs(t, err, repository.ErrUnavailable)
+	store.AssertNumberOfCalls(t, "LinkStats", 1)
+}
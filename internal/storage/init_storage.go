@@ -9,11 +9,10 @@ import (
 	"github.com/Thoustick/SlugKiller/infrastructure/db"
 	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/internal/storage/mem"
-	"github.com/Thoustick/SlugKiller/internal/storage/pg"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 )
 
-func InitStorage(ctx context.Context, cfg *config.Config, log logger.Logger) (repository.URLRepository, error) {
+func InitStorage(ctx context.Context, cfg *config.Config, log logger.Logger) (repository.Store, error) {
 	switch cfg.StorageType {
 	case "postgres":
 		if cfg.DatabaseURL == "" {
@@ -23,7 +22,7 @@ func InitStorage(ctx context.Context, cfg *config.Config, log logger.Logger) (re
 		if err != nil {
 			return nil, err
 		}
-		return pg.NewRepo(db.Pool, log), nil
+		return newPostgresStore(db), nil
 
 	case "memory":
 		return mem.NewRepo(log), nil
@@ -0,0 +1,56 @@
+package mem
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// APIKeyStore хранит API-ключи в памяти процесса.
+type APIKeyStore struct {
+	mu     sync.RWMutex
+	byHash map[string]*model.APIKey
+	nextID int64
+}
+
+var _ repository.APIKeyStore = (*APIKeyStore)(nil)
+
+func NewAPIKeyStore() *APIKeyStore {
+	return &APIKeyStore{
+		byHash: make(map[string]*model.APIKey),
+	}
+}
+
+func (s *APIKeyStore) CreateAPIKey(_ context.Context, key *model.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.byHash[key.KeyHash]; exists {
+		return repository.ErrAlreadyExists
+	}
+
+	s.nextID++
+	key.ID = s.nextID
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	stored := *key
+	s.byHash[key.KeyHash] = &stored
+	return nil
+}
+
+func (s *APIKeyStore) GetAPIKeyByHash(_ context.Context, hash string) (*model.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.byHash[hash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	found := *key
+	return &found, nil
+}
@@ -0,0 +1,33 @@
+package mem_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+)
+
+func TestAPIKeyStore(t *testing.T) {
+	store := mem.NewAPIKeyStore()
+	ctx := context.Background()
+
+	key := &model.APIKey{OwnerID: "team-a", Name: "ci", KeyHash: "hash-1"}
+	require.NoError(t, store.CreateAPIKey(ctx, key))
+	assert.Equal(t, int64(1), key.ID)
+	assert.False(t, key.CreatedAt.IsZero())
+
+	got, err := store.GetAPIKeyByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", got.OwnerID)
+
+	err = store.CreateAPIKey(ctx, &model.APIKey{OwnerID: "team-b", KeyHash: "hash-1"})
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+
+	_, err = store.GetAPIKeyByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
@@ -0,0 +1,39 @@
+package mem
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// ClickStore хранит клики в памяти, сгруппированными по slug.
+type ClickStore struct {
+	mu     sync.RWMutex
+	bySlug map[string][]model.Click
+}
+
+func NewClickStore() *ClickStore {
+	return &ClickStore{bySlug: make(map[string][]model.Click)}
+}
+
+var _ repository.ClickWriter = (*ClickStore)(nil)
+
+func (s *ClickStore) SaveClicks(_ context.Context, clicks []model.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range clicks {
+		s.bySlug[c.Slug] = append(s.bySlug[c.Slug], c)
+	}
+	return nil
+}
+
+// Clicks возвращает копию кликов по slug.
+func (s *ClickStore) Clicks(slug string) []model.Click {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]model.Click(nil), s.bySlug[slug]...)
+}
@@ -2,6 +2,7 @@ package mem
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,18 +12,23 @@ import (
 )
 
 type InMemoryRepo struct {
-	mu       sync.RWMutex
-	bySlug   map[string]*model.Link
-	byOrigin map[string]*model.Link
-	logger   logger.Logger
+	mu          sync.RWMutex
+	bySlug      map[string]*model.Link
+	byCanonical map[string]*model.Link // последняя созданная ссылка по владельцу и каноническому URL
+	nextID      int64
+	logger      logger.Logger
+
+	pool   []string            // пул заранее сгенерированных slug
+	pooled map[string]struct{} // те же slug для проверки повторов
 }
 
 // New возвращает in-memory хранилище, реализующее URLRepository
 func New(log logger.Logger) *InMemoryRepo {
 	return &InMemoryRepo{
-		bySlug:   make(map[string]*model.Link),
-		byOrigin: make(map[string]*model.Link),
-		logger:   log,
+		bySlug:      make(map[string]*model.Link),
+		byCanonical: make(map[string]*model.Link),
+		logger:      log,
+		pooled:      make(map[string]struct{}),
 	}
 }
 
@@ -40,12 +46,14 @@ func (r *InMemoryRepo) GetBySlug(_ context.Context, slug string) (*model.Link, e
 	return link, nil
 }
 
-func (r *InMemoryRepo) GetByOriginalURL(_ context.Context, original string) (*model.Link, error) {
+// GetByCanonicalURL возвращает последнюю созданную ссылку владельца с этим каноническим URL,
+// если она ещё не истекла.
+func (r *InMemoryRepo) GetByCanonicalURL(_ context.Context, ownerID, key string) (*model.Link, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	link, ok := r.byOrigin[original]
-	if !ok {
+	link, ok := r.byCanonical[canonicalKey(ownerID, key)]
+	if !ok || link.IsExpired(time.Now()) {
 		return nil, repository.ErrNotFound
 	}
 	return link, nil
@@ -58,14 +66,71 @@ func (r *InMemoryRepo) Create(_ context.Context, link *model.Link) error {
 	if _, exists := r.bySlug[link.Slug]; exists {
 		return repository.ErrAlreadyExists
 	}
-	if _, exists := r.byOrigin[link.URL]; exists {
-		return repository.ErrAlreadyExists
-	}
 
-	link.ID = int64(len(r.bySlug) + 1)
+	r.nextID++
+	link.ID = r.nextID
 	link.CreatedAt = time.Now()
+	if link.Status == "" {
+		link.Status = model.LinkStatusActive
+	}
 
 	r.bySlug[link.Slug] = link
-	r.byOrigin[link.URL] = link
+	r.byCanonical[dedupeKey(link)] = link
+	return nil
+}
+
+func (r *InMemoryRepo) Update(_ context.Context, link *model.Link) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.bySlug[link.Slug]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	updated := *current
+	updated.URL = link.URL
+	updated.CanonicalURL = link.CanonicalURL
+	updated.Status = link.Status
+	updated.ExpiresAt = link.ExpiresAt
+	updated.RedirectType = link.RedirectType
+	updated.QueryPassthrough = link.QueryPassthrough
+	updated.PathPassthrough = link.PathPassthrough
+	updated.UTM = link.UTM
+	updated.UTMTemplate = link.UTMTemplate
+	updated.Rules = slices.Clone(link.Rules)
+	updated.Targets = slices.Clone(link.Targets)
+	updated.StickyTargets = link.StickyTargets
+
+	if r.byCanonical[dedupeKey(current)] == current {
+		delete(r.byCanonical, dedupeKey(current))
+	}
+	r.bySlug[link.Slug] = &updated
+	r.byCanonical[dedupeKey(&updated)] = &updated
 	return nil
 }
+
+func (r *InMemoryRepo) Delete(_ context.Context, slug string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link, ok := r.bySlug[slug]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	delete(r.bySlug, slug)
+	if r.byCanonical[dedupeKey(link)] == link {
+		delete(r.byCanonical, dedupeKey(link))
+	}
+	return nil
+}
+
+// canonicalKey — ключ индекса дублей: дубли ищутся только среди ссылок одного владельца.
+func canonicalKey(ownerID, key string) string {
+	return ownerID + "\x00" + key
+}
+
+func dedupeKey(link *model.Link) string {
+	return canonicalKey(link.OwnerID, link.DedupeKey())
+}
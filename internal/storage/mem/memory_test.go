@@ -3,6 +3,7 @@ package mem_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -30,7 +31,7 @@ func TestInMemoryRepo_Create_And_Get(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, link.URL, gotBySlug.URL)
 
-	gotByOriginal, err := repo.GetByOriginalURL(ctx, "https://example.com")
+	gotByOriginal, err := repo.GetByCanonicalURL(ctx, "", "https://example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, link.Slug, gotByOriginal.Slug)
 }
@@ -44,12 +45,12 @@ func TestInMemoryRepo_GetBySlug_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, repository.ErrNotFound)
 }
 
-func TestInMemoryRepo_GetByOriginalURL_NotFound(t *testing.T) {
+func TestInMemoryRepo_GetByCanonicalURL_NotFound(t *testing.T) {
 	log := new(mocks.MockLogger)
 	repo := mem.NewRepo(log)
 	ctx := context.Background()
 
-	_, err := repo.GetByOriginalURL(ctx, "https://notfound.com")
+	_, err := repo.GetByCanonicalURL(ctx, "", "https://notfound.com")
 	assert.ErrorIs(t, err, repository.ErrNotFound)
 }
 
@@ -81,12 +82,108 @@ func TestInMemoryRepo_Create_DuplicateURL(t *testing.T) {
 		Slug: "slug1",
 		URL:  "https://dupe.com",
 	}
-	_ = repo.Create(ctx, link)
+	require.NoError(t, repo.Create(ctx, link))
 
+	// Один URL может иметь несколько ссылок, поиск по URL отдаёт последнюю
 	dup := &model.Link{
 		Slug: "slug2",
 		URL:  "https://dupe.com",
 	}
-	err := repo.Create(ctx, dup)
-	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	require.NoError(t, repo.Create(ctx, dup))
+
+	got, err := repo.GetByCanonicalURL(ctx, "", "https://dupe.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "slug2", got.Slug)
+}
+
+func TestInMemoryRepo_GetByCanonicalURL_ScopedByOwner(t *testing.T) {
+	repo := mem.NewRepo(new(mocks.MockLogger))
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &model.Link{Slug: "anon", URL: "https://shared.com"}))
+	require.NoError(t, repo.Create(ctx, &model.Link{Slug: "team", URL: "https://shared.com", OwnerID: "team-a"}))
+
+	got, err := repo.GetByCanonicalURL(ctx, "", "https://shared.com")
+	require.NoError(t, err)
+	assert.Equal(t, "anon", got.Slug)
+
+	got, err = repo.GetByCanonicalURL(ctx, "team-a", "https://shared.com")
+	require.NoError(t, err)
+	assert.Equal(t, "team", got.Slug)
+
+	_, err = repo.GetByCanonicalURL(ctx, "team-b", "https://shared.com")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestInMemoryRepo_GetByCanonicalURL_Expired(t *testing.T) {
+	log := new(mocks.MockLogger)
+	repo := mem.NewRepo(log)
+	ctx := context.Background()
+
+	expiredAt := time.Now().Add(-time.Minute)
+	link := &model.Link{
+		Slug:      "old",
+		URL:       "https://expired.com",
+		ExpiresAt: &expiredAt,
+	}
+	require.NoError(t, repo.Create(ctx, link))
+
+	_, err := repo.GetByCanonicalURL(ctx, "", "https://expired.com")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// По slug истёкшая ссылка по-прежнему доступна — решение принимает сервис
+	got, err := repo.GetBySlug(ctx, "old")
+	assert.NoError(t, err)
+	assert.True(t, got.IsExpired(time.Now()))
+}
+
+func TestInMemoryRepo_Update(t *testing.T) {
+	log := new(mocks.MockLogger)
+	repo := mem.NewRepo(log)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &model.Link{Slug: "abc", URL: "https://old.com"}))
+
+	err := repo.Update(ctx, &model.Link{
+		Slug:   "abc",
+		URL:    "https://new.com",
+		Status: model.LinkStatusDisabled,
+	})
+	require.NoError(t, err)
+
+	got, err := repo.GetBySlug(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://new.com", got.URL)
+	assert.Equal(t, model.LinkStatusDisabled, got.Status)
+	assert.False(t, got.CreatedAt.IsZero(), "created_at must be preserved")
+
+	_, err = repo.GetByCanonicalURL(ctx, "", "https://old.com")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	gotByURL, err := repo.GetByCanonicalURL(ctx, "", "https://new.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", gotByURL.Slug)
+}
+
+func TestInMemoryRepo_Update_NotFound(t *testing.T) {
+	repo := mem.NewRepo(new(mocks.MockLogger))
+
+	err := repo.Update(context.Background(), &model.Link{Slug: "missing", URL: "https://x.com"})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestInMemoryRepo_Delete(t *testing.T) {
+	log := new(mocks.MockLogger)
+	repo := mem.NewRepo(log)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &model.Link{Slug: "abc", URL: "https://example.com"}))
+	require.NoError(t, repo.Delete(ctx, "abc"))
+
+	_, err := repo.GetBySlug(ctx, "abc")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.GetByCanonicalURL(ctx, "", "https://example.com")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.ErrorIs(t, repo.Delete(ctx, "abc"), repository.ErrNotFound)
 }
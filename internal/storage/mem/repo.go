@@ -5,15 +5,21 @@ import (
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 )
 
-// MemRepo просто обёртка над InMemoryRepo (можно вернуть напрямую — дело вкуса)
+// Repo объединяет in-memory хранилища ссылок, кликов и API-ключей
 type Repo struct {
 	*InMemoryRepo
+	*ClickStore
+	*Sequence
+	*APIKeyStore
 }
 
-var _ repository.URLRepository = (*Repo)(nil)
+var _ repository.Store = (*Repo)(nil)
 
 func NewRepo(log logger.Logger) *Repo {
 	return &Repo{
 		InMemoryRepo: New(log),
+		ClickStore:   NewClickStore(),
+		Sequence:     &Sequence{},
+		APIKeyStore:  NewAPIKeyStore(),
 	}
 }
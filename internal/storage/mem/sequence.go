@@ -0,0 +1,19 @@
+package mem
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+// Sequence — счётчик для последовательных стратегий генерации slug. Нумерация начинается с 1.
+type Sequence struct {
+	last atomic.Int64
+}
+
+var _ repository.SlugSequence = (*Sequence)(nil)
+
+func (s *Sequence) NextSlugID(_ context.Context) (int64, error) {
+	return s.last.Add(1), nil
+}
@@ -0,0 +1,42 @@
+package mem
+
+import (
+	"context"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+var _ repository.SlugPoolStore = (*InMemoryRepo)(nil)
+
+func (r *InMemoryRepo) AddPooledSlugs(_ context.Context, slugs []string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	added := 0
+	for _, slug := range slugs {
+		if _, taken := r.bySlug[slug]; taken {
+			continue
+		}
+		if _, dup := r.pooled[slug]; dup {
+			continue
+		}
+		r.pooled[slug] = struct{}{}
+		r.pool = append(r.pool, slug)
+		added++
+	}
+	return added, nil
+}
+
+func (r *InMemoryRepo) TakePooledSlugs(_ context.Context, n int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n = min(n, len(r.pool))
+	taken := make([]string, n)
+	copy(taken, r.pool[len(r.pool)-n:])
+	r.pool = r.pool[:len(r.pool)-n]
+	for _, slug := range taken {
+		delete(r.pooled, slug)
+	}
+	return taken, nil
+}
@@ -0,0 +1,35 @@
+package mem_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestInMemoryRepo_SlugPool(t *testing.T) {
+	repo := mem.NewRepo(new(mocks.MockLogger))
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &model.Link{Slug: "taken", URL: "https://example.com"}))
+
+	added, err := repo.AddPooledSlugs(ctx, []string{"a", "b", "taken", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, added, "занятые и повторные кандидаты пропускаются")
+
+	first, err := repo.TakePooledSlugs(ctx, 1)
+	require.NoError(t, err)
+	rest, err := repo.TakePooledSlugs(ctx, 10)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"a", "b"}, append(first, rest...))
+
+	empty, err := repo.TakePooledSlugs(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
@@ -0,0 +1,94 @@
+package mem
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+)
+
+var _ repository.StatsReader = (*ClickStore)(nil)
+
+// LinkStats считает ту же статистику, что и Postgres-реализация, перебором кликов в памяти.
+func (s *ClickStore) LinkStats(_ context.Context, slug string, q model.StatsQuery) (*model.LinkStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	step := model.IntervalDuration(q.Interval)
+	visitors := make(map[string]struct{})
+	buckets := make(map[time.Time]*bucketAcc)
+	referrers := make(map[string]int64)
+	families := make(map[string]int64)
+	variants := make(map[string]int64)
+
+	stats := &model.LinkStats{}
+	for _, c := range s.bySlug[slug] {
+		if c.ClickedAt.Before(q.From) || !c.ClickedAt.Before(q.To) {
+			continue
+		}
+
+		stats.TotalClicks++
+		visitors[c.IPHash] = struct{}{}
+
+		start := c.ClickedAt.UTC().Truncate(step)
+		acc, ok := buckets[start]
+		if !ok {
+			acc = &bucketAcc{visitors: make(map[string]struct{})}
+			buckets[start] = acc
+		}
+		acc.clicks++
+		acc.visitors[c.IPHash] = struct{}{}
+
+		if c.Referrer != "" {
+			referrers[c.Referrer]++
+		}
+		families[c.UAFamily]++
+		if c.Variant != "" {
+			variants[c.Variant]++
+		}
+	}
+	stats.UniqueVisitors = int64(len(visitors))
+
+	for start, acc := range buckets {
+		stats.Series = append(stats.Series, model.StatsBucket{
+			Start:          start,
+			Clicks:         acc.clicks,
+			UniqueVisitors: int64(len(acc.visitors)),
+		})
+	}
+	sort.Slice(stats.Series, func(i, j int) bool {
+		return stats.Series[i].Start.Before(stats.Series[j].Start)
+	})
+
+	stats.TopReferrers = topCounts(referrers, q.Limit)
+	stats.TopUserAgents = topCounts(families, q.Limit)
+	// Варианты не ограничиваются limit: для сравнения A/B нужны все
+	stats.Variants = topCounts(variants, 0)
+	return stats, nil
+}
+
+type bucketAcc struct {
+	clicks   int64
+	visitors map[string]struct{}
+}
+
+// topCounts сортирует значения по убыванию числа переходов (при равенстве — по значению).
+func topCounts(counts map[string]int64, limit int) []model.StatsCount {
+	top := make([]model.StatsCount, 0, len(counts))
+	for value, clicks := range counts {
+		top = append(top, model.StatsCount{Value: value, Clicks: clicks})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Clicks != top[j].Clicks {
+			return top[i].Clicks > top[j].Clicks
+		}
+		return top[i].Value < top[j].Value
+	})
+
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
@@ -0,0 +1,68 @@
+package mem_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/storage/mem"
+)
+
+func TestClickStore_LinkStats(t *testing.T) {
+	store := mem.NewClickStore()
+	ctx := context.Background()
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.SaveClicks(ctx, []model.Click{
+		{Slug: "abc", ClickedAt: day.Add(time.Hour), Referrer: "https://t.me", UAFamily: "Chrome", IPHash: "h1"},
+		{Slug: "abc", ClickedAt: day.Add(2 * time.Hour), Referrer: "https://t.me", UAFamily: "Chrome", IPHash: "h1"},
+		{Slug: "abc", ClickedAt: day.Add(26 * time.Hour), UAFamily: "Firefox", IPHash: "h2"},
+		{Slug: "abc", ClickedAt: day.Add(-time.Hour), UAFamily: "Firefox", IPHash: "h3"}, // вне диапазона
+		{Slug: "other", ClickedAt: day.Add(time.Hour), UAFamily: "Safari", IPHash: "h4"},
+	}))
+
+	stats, err := store.LinkStats(ctx, "abc", model.StatsQuery{
+		From:     day,
+		To:       day.Add(48 * time.Hour),
+		Interval: model.StatsIntervalDay,
+		Limit:    10,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stats.TotalClicks)
+	assert.Equal(t, int64(2), stats.UniqueVisitors)
+	assert.Equal(t, []model.StatsBucket{
+		{Start: day, Clicks: 2, UniqueVisitors: 1},
+		{Start: day.Add(24 * time.Hour), Clicks: 1, UniqueVisitors: 1},
+	}, stats.Series)
+	assert.Equal(t, []model.StatsCount{{Value: "https://t.me", Clicks: 2}}, stats.TopReferrers)
+	assert.Equal(t, []model.StatsCount{{Value: "Chrome", Clicks: 2}, {Value: "Firefox", Clicks: 1}}, stats.TopUserAgents)
+}
+
+func TestClickStore_LinkStatsVariantsIgnoreLimit(t *testing.T) {
+	store := mem.NewClickStore()
+	ctx := context.Background()
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, store.SaveClicks(ctx, []model.Click{
+		{Slug: "abc", ClickedAt: day.Add(time.Hour), Variant: "a", UAFamily: "Chrome", IPHash: "h1"},
+		{Slug: "abc", ClickedAt: day.Add(time.Hour), Variant: "a", UAFamily: "Firefox", IPHash: "h2"},
+		{Slug: "abc", ClickedAt: day.Add(time.Hour), Variant: "b", UAFamily: "Safari", IPHash: "h3"},
+	}))
+
+	stats, err := store.LinkStats(ctx, "abc", model.StatsQuery{
+		From:     day,
+		To:       day.Add(24 * time.Hour),
+		Interval: model.StatsIntervalDay,
+		Limit:    1,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, stats.TopUserAgents, 1)
+	// Для сравнения A/B нужны все варианты, а не первые limit
+	assert.Equal(t, []model.StatsCount{{Value: "a", Clicks: 2}, {Value: "b", Clicks: 1}}, stats.Variants)
+}
@@ -0,0 +1,65 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+type PostgresAPIKeyStore struct {
+	db     DBExecutor
+	logger logger.Logger
+}
+
+func NewPostgresAPIKeyStore(db DBExecutor, l logger.Logger) *PostgresAPIKeyStore {
+	return &PostgresAPIKeyStore{
+		db:     db,
+		logger: l,
+	}
+}
+
+var _ repository.APIKeyStore = (*PostgresAPIKeyStore)(nil)
+
+const (
+	createAPIKeyQuery = `INSERT INTO api_keys (owner_id, name, key_hash)
+		VALUES ($1, $2, $3) RETURNING id, created_at`
+	getAPIKeyByHashQuery = `SELECT id, owner_id, name, key_hash, created_at, revoked_at
+		FROM api_keys WHERE key_hash = $1`
+)
+
+func (s *PostgresAPIKeyStore) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	err := s.db.QueryRow(ctx, createAPIKeyQuery, key.OwnerID, key.Name, key.KeyHash).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return repository.ErrAlreadyExists
+		}
+		s.logger.Error("failed to create API key", err, map[string]interface{}{
+			"owner_id": key.OwnerID,
+		})
+		return fmt.Errorf("create API key: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresAPIKeyStore) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var key model.APIKey
+
+	err := s.db.QueryRow(ctx, getAPIKeyByHashQuery, hash).
+		Scan(&key.ID, &key.OwnerID, &key.Name, &key.KeyHash, &key.CreatedAt, &key.RevokedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		s.logger.Error("failed to get API key", err, nil)
+		return nil, fmt.Errorf("get API key: %w", err)
+	}
+	return &key, nil
+}
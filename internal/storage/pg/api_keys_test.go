@@ -0,0 +1,88 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	t.Run("сохраняет ключ и заполняет ID", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+		createdAt := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+		rowMock.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			dest := args.Get(0).([]any)
+			*(dest[0].(*int64)) = 5
+			*(dest[1].(*time.Time)) = createdAt
+		}).Return(nil).Once()
+		dbMock.On("QueryRow", mock.Anything, createAPIKeyQuery, []interface{}{"team-a", "ci", "hash"}).
+			Return(rowMock).Once()
+
+		s := NewPostgresAPIKeyStore(dbMock, &mocks.MockLogger{})
+		key := &model.APIKey{OwnerID: "team-a", Name: "ci", KeyHash: "hash"}
+
+		assert.NoError(t, s.CreateAPIKey(context.Background(), key))
+		assert.Equal(t, int64(5), key.ID)
+		assert.Equal(t, createdAt, key.CreatedAt)
+	})
+
+	t.Run("повтор хеша — ErrAlreadyExists", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+
+		rowMock.On("Scan", mock.Anything).Return(&pgconn.PgError{Code: "23505"}).Once()
+		dbMock.On("QueryRow", mock.Anything, createAPIKeyQuery, mock.Anything).Return(rowMock).Once()
+
+		s := NewPostgresAPIKeyStore(dbMock, &mocks.MockLogger{})
+		err := s.CreateAPIKey(context.Background(), &model.APIKey{OwnerID: "team-a", KeyHash: "hash"})
+
+		assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	})
+}
+
+func TestGetAPIKeyByHash(t *testing.T) {
+	t.Run("ключ найден", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+
+		rowMock.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			dest := args.Get(0).([]any)
+			*(dest[0].(*int64)) = 5
+			*(dest[1].(*string)) = "team-a"
+			*(dest[3].(*string)) = "hash"
+		}).Return(nil).Once()
+		dbMock.On("QueryRow", mock.Anything, getAPIKeyByHashQuery, []interface{}{"hash"}).Return(rowMock).Once()
+
+		s := NewPostgresAPIKeyStore(dbMock, &mocks.MockLogger{})
+		key, err := s.GetAPIKeyByHash(context.Background(), "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), key.ID)
+		assert.Equal(t, "team-a", key.OwnerID)
+		assert.False(t, key.IsRevoked())
+	})
+
+	t.Run("ключ не найден", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+
+		rowMock.On("Scan", mock.Anything).Return(pgx.ErrNoRows).Once()
+		dbMock.On("QueryRow", mock.Anything, getAPIKeyByHashQuery, mock.Anything).Return(rowMock).Once()
+
+		s := NewPostgresAPIKeyStore(dbMock, &mocks.MockLogger{})
+		_, err := s.GetAPIKeyByHash(context.Background(), "nope")
+
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -0,0 +1,60 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+type PostgresClickWriter struct {
+	db     DBExecutor
+	logger logger.Logger
+}
+
+func NewPostgresClickWriter(db DBExecutor, l logger.Logger) *PostgresClickWriter {
+	return &PostgresClickWriter{
+		db:     db,
+		logger: l,
+	}
+}
+
+var _ repository.ClickWriter = (*PostgresClickWriter)(nil)
+
+// Вся пачка вставляется одним запросом: массивы разворачиваются через unnest
+const insertClicksQuery = `INSERT INTO clicks (slug, clicked_at, referrer, user_agent, ua_family, ip_hash, variant)
+	SELECT * FROM unnest($1::text[], $2::timestamptz[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[])`
+
+func (w *PostgresClickWriter) SaveClicks(ctx context.Context, clicks []model.Click) error {
+	if len(clicks) == 0 {
+		return nil
+	}
+
+	slugs := make([]string, len(clicks))
+	clickedAt := make([]time.Time, len(clicks))
+	referrers := make([]string, len(clicks))
+	userAgents := make([]string, len(clicks))
+	uaFamilies := make([]string, len(clicks))
+	ipHashes := make([]string, len(clicks))
+	variants := make([]string, len(clicks))
+	for i, c := range clicks {
+		slugs[i] = c.Slug
+		clickedAt[i] = c.ClickedAt
+		referrers[i] = c.Referrer
+		userAgents[i] = c.UserAgent
+		uaFamilies[i] = c.UAFamily
+		ipHashes[i] = c.IPHash
+		variants[i] = c.Variant
+	}
+
+	_, err := w.db.Exec(ctx, insertClicksQuery, slugs, clickedAt, referrers, userAgents, uaFamilies, ipHashes, variants)
+	if err != nil {
+		w.logger.Error("failed to insert clicks", err, map[string]interface{}{
+			"count": len(clicks),
+		})
+		return err
+	}
+	return nil
+}
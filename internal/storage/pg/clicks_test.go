@@ -0,0 +1,66 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestSaveClicks(t *testing.T) {
+	t.Run("пачка вставляется одним запросом", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		at := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+		clicks := []model.Click{
+			{Slug: "a", ClickedAt: at, Referrer: "r1", UserAgent: "ua1", UAFamily: "Chrome", IPHash: "h1"},
+			{Slug: "b", ClickedAt: at, Referrer: "r2", UserAgent: "ua2", UAFamily: "Firefox", IPHash: "h2", Variant: "b"},
+		}
+
+		dbMock.On("Exec", mock.Anything, insertClicksQuery, []interface{}{
+			[]string{"a", "b"},
+			[]time.Time{at, at},
+			[]string{"r1", "r2"},
+			[]string{"ua1", "ua2"},
+			[]string{"Chrome", "Firefox"},
+			[]string{"h1", "h2"},
+			[]string{"", "b"},
+		}).Return(pgconn.NewCommandTag("INSERT 0 2"), nil).Once()
+
+		w := &PostgresClickWriter{db: dbMock, logger: loggerMock}
+		assert.NoError(t, w.SaveClicks(context.Background(), clicks))
+
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("пустая пачка не ходит в БД", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		w := &PostgresClickWriter{db: dbMock, logger: &mocks.MockLogger{}}
+
+		assert.NoError(t, w.SaveClicks(context.Background(), nil))
+		dbMock.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ошибка БД логируется", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		dbErr := errors.New("db failure")
+		dbMock.On("Exec", mock.Anything, insertClicksQuery, mock.Anything).
+			Return(pgconn.NewCommandTag(""), dbErr).Once()
+		loggerMock.On("Error", "failed to insert clicks", dbErr, mock.Anything).Once()
+
+		w := &PostgresClickWriter{db: dbMock, logger: loggerMock}
+		assert.ErrorIs(t, w.SaveClicks(context.Background(), []model.Click{{Slug: "a"}}), dbErr)
+
+		loggerMock.AssertExpectations(t)
+	})
+}
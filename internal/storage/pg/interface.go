@@ -10,5 +10,6 @@ import (
 // DBExecutor определяет поведение, нужное для чтения/записи в БД.
 type DBExecutor interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
 }
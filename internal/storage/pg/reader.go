@@ -2,10 +2,12 @@ package pg
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Thoustick/SlugKiller/internal/model"
 	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/pkg/logger"
+	"github.com/jackc/pgx/v5"
 )
 
 type PostgresReader struct {
@@ -22,12 +24,28 @@ func NewPostgresReader(db DBExecutor, l logger.Logger) *PostgresReader {
 
 var _ repository.URLReader = (*PostgresReader)(nil)
 
+// linkColumns — колонки ссылки в порядке linkFields; правила перехода собираются в JSON-массив.
+const linkColumns = `id, slug, url, status, created_at, expires_at, canonical_url, owner_id, redirect_type,
+		query_passthrough, path_passthrough, utm, utm_template, targets, sticky_targets,
+		(SELECT json_agg(json_build_object('os', r.os, 'device', r.device, 'bot', r.bot, 'url', r.url)
+			ORDER BY r.position)
+			FROM link_rules r WHERE r.slug = urls.slug) AS rules`
+
+const (
+	getBySlugQuery         = `SELECT ` + linkColumns + ` FROM urls WHERE slug = $1`
+	getByCanonicalURLQuery = `SELECT ` + linkColumns + ` FROM urls
+		WHERE canonical_url = $1 AND owner_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY id DESC LIMIT 1`
+)
+
 func (r *PostgresReader) GetBySlug(ctx context.Context, slug string) (*model.Link, error) {
-	const query = `SELECT id, slug, url, created_at FROM urls WHERE slug = $1`
 	var link model.Link
 
-	err := r.db.QueryRow(ctx, query, slug).Scan(&link.ID, &link.Slug, &link.URL, &link.CreatedAt)
+	err := r.db.QueryRow(ctx, getBySlugQuery, slug).Scan(linkFields(&link)...)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
 		r.logger.Error("failed to get link by slug", err, map[string]interface{}{
 			"slug": slug,
 		})
@@ -37,17 +55,29 @@ func (r *PostgresReader) GetBySlug(ctx context.Context, slug string) (*model.Lin
 	return &link, nil
 }
 
-func (r *PostgresReader) GetByOriginalURL(ctx context.Context, url string) (*model.Link, error) {
-	const query = `SELECT id, slug, url, created_at FROM urls WHERE url = $1`
+// GetByCanonicalURL возвращает самую свежую неистёкшую ссылку владельца с указанным каноническим URL.
+func (r *PostgresReader) GetByCanonicalURL(ctx context.Context, ownerID, key string) (*model.Link, error) {
 	var link model.Link
 
-	err := r.db.QueryRow(ctx, query, url).Scan(&link.ID, &link.Slug, &link.URL, &link.CreatedAt)
+	err := r.db.QueryRow(ctx, getByCanonicalURLQuery, key, ownerID).Scan(linkFields(&link)...)
 	if err != nil {
-		r.logger.Error("failed to get link by original URL", err, map[string]interface{}{
-			"url": url,
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		r.logger.Error("failed to get link by canonical URL", err, map[string]interface{}{
+			"url": key,
 		})
 		return nil, err
 	}
 
 	return &link, nil
 }
+
+// linkFields возвращает указатели на поля ссылки в порядке колонок запросов чтения.
+func linkFields(link *model.Link) []any {
+	return []any{
+		&link.ID, &link.Slug, &link.URL, &link.Status, &link.CreatedAt, &link.ExpiresAt, &link.CanonicalURL, &link.OwnerID,
+		&link.RedirectType, &link.QueryPassthrough, &link.PathPassthrough, &link.UTM, &link.UTMTemplate,
+		&link.Targets, &link.StickyTargets, &link.Rules,
+	}
+}
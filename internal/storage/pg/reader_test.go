@@ -6,9 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/Thoustick/SlugKiller/internal/repository"
 	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
 )
 
@@ -26,12 +28,13 @@ func TestGetBySlug(t *testing.T) {
 			*(dest[0].(*int64)) = 42                                              // link.ID
 			*(dest[1].(*string)) = "test-slug"                                    // link.Slug
 			*(dest[2].(*string)) = "https://example.com"                          // link.URL
-			*(dest[3].(*time.Time)) = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC) // link.CreatedAt
+			*(dest[3].(*string)) = "active"                                       // link.Status
+			*(dest[4].(*time.Time)) = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC) // link.CreatedAt
 		}).Return(nil) // нет ошибки
 
 		// 3. Программируем DBExecutor: QueryRow(...) вернёт rowMock
 		dbMock.On("QueryRow", mock.Anything,
-			"SELECT id, slug, url, created_at FROM urls WHERE slug = $1",
+			getBySlugQuery,
 			[]interface{}{"test-slug"}).Return(rowMock)
 
 		// loggerMock может вызываться или не вызываться. Здесь
@@ -52,6 +55,7 @@ func TestGetBySlug(t *testing.T) {
 		assert.Equal(t, int64(42), link.ID)
 		assert.Equal(t, "test-slug", link.Slug)
 		assert.Equal(t, "https://example.com", link.URL)
+		assert.Equal(t, "active", link.Status)
 		assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), link.CreatedAt)
 
 		// 6. Проверяем, что все ожидаемые вызовы произошли
@@ -69,7 +73,7 @@ func TestGetBySlug(t *testing.T) {
 		rowMock.On("Scan", mock.Anything).Return(errors.New("no rows in result set"))
 
 		dbMock.On("QueryRow", mock.Anything,
-			"SELECT id, slug, url, created_at FROM urls WHERE slug = $1",
+			getBySlugQuery,
 			[]interface{}{"unknown"}).Return(rowMock)
 
 		// Ожидаем, что logger.Error(...) будет вызван
@@ -96,7 +100,7 @@ func TestGetBySlug(t *testing.T) {
 		rowMock.On("Scan", mock.Anything).Return(errors.New("no rows in result set"))
 
 		dbMock.On("QueryRow", mock.Anything,
-			"SELECT id, slug, url, created_at FROM urls WHERE slug = $1",
+			getBySlugQuery,
 			[]interface{}{""}).Return(rowMock)
 
 		loggerMock.On("Error", "failed to get link by slug", mock.Anything, mock.Anything).Once()
@@ -111,9 +115,27 @@ func TestGetBySlug(t *testing.T) {
 		rowMock.AssertExpectations(t)
 		loggerMock.AssertExpectations(t)
 	})
+
+	t.Run("pgx.ErrNoRows превращается в repository.ErrNotFound", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+		loggerMock := &mocks.MockLogger{}
+
+		rowMock.On("Scan", mock.Anything).Return(pgx.ErrNoRows)
+		dbMock.On("QueryRow", mock.Anything, getBySlugQuery, []interface{}{"missing"}).Return(rowMock)
+
+		r := &PostgresReader{db: dbMock, logger: loggerMock}
+
+		link, err := r.GetBySlug(context.Background(), "missing")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		assert.Nil(t, link)
+
+		// отсутствие записи — штатная ситуация, в лог ошибок не пишем
+		loggerMock.AssertNotCalled(t, "Error", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
-func TestGetByOriginalURL(t *testing.T) {
+func TestGetByCanonicalURL(t *testing.T) {
 	t.Run("успешное получение записи по URL", func(t *testing.T) {
 		dbMock := &mocks.MockDBExecutor{}
 		rowMock := &mocks.MockRow{}
@@ -124,22 +146,25 @@ func TestGetByOriginalURL(t *testing.T) {
 			*(dest[0].(*int64)) = 99
 			*(dest[1].(*string)) = "slug-99"
 			*(dest[2].(*string)) = "https://test.com"
-			*(dest[3].(*time.Time)) = time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC)
+			*(dest[3].(*string)) = "active"
+			*(dest[4].(*time.Time)) = time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC)
+			*(dest[7].(*string)) = "team-a"
 		}).Return(nil)
 
 		dbMock.On("QueryRow", mock.Anything,
-			"SELECT id, slug, url, created_at FROM urls WHERE url = $1",
-			[]interface{}{"https://test.com"}).Return(rowMock)
+			getByCanonicalURLQuery,
+			[]interface{}{"https://test.com", "team-a"}).Return(rowMock)
 
 		r := &PostgresReader{db: dbMock, logger: loggerMock}
 
-		link, err := r.GetByOriginalURL(context.Background(), "https://test.com")
+		link, err := r.GetByCanonicalURL(context.Background(), "team-a", "https://test.com")
 		assert.NoError(t, err)
 		assert.NotNil(t, link)
 		assert.Equal(t, int64(99), link.ID)
 		assert.Equal(t, "slug-99", link.Slug)
 		assert.Equal(t, "https://test.com", link.URL)
 		assert.Equal(t, time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC), link.CreatedAt)
+		assert.Equal(t, "team-a", link.OwnerID)
 
 		dbMock.AssertExpectations(t)
 		rowMock.AssertExpectations(t)
@@ -153,14 +178,14 @@ func TestGetByOriginalURL(t *testing.T) {
 
 		rowMock.On("Scan", mock.Anything).Return(errors.New("no rows"))
 		dbMock.On("QueryRow", mock.Anything,
-			"SELECT id, slug, url, created_at FROM urls WHERE url = $1",
-			[]interface{}{"https://nope.com"}).Return(rowMock)
+			getByCanonicalURLQuery,
+			[]interface{}{"https://nope.com", ""}).Return(rowMock)
 
-		loggerMock.On("Error", "failed to get link by original URL", mock.Anything, mock.Anything).Once()
+		loggerMock.On("Error", "failed to get link by canonical URL", mock.Anything, mock.Anything).Once()
 
 		r := &PostgresReader{db: dbMock, logger: loggerMock}
 
-		link, err := r.GetByOriginalURL(context.Background(), "https://nope.com")
+		link, err := r.GetByCanonicalURL(context.Background(), "", "https://nope.com")
 		assert.Error(t, err)
 		assert.Nil(t, link)
 
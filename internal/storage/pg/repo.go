@@ -5,19 +5,29 @@ import (
 	"github.com/Thoustick/SlugKiller/pkg/logger"
 )
 
-// PostgresRepo объединяет ридер и райтер в один объект
+// PostgresRepo объединяет ридер, райтер, хранилище кликов, статистику, счётчик, пул slug и API-ключи в один объект
 type PostgresRepo struct {
 	*PostgresReader
 	*PostgresWriter
+	*PostgresClickWriter
+	*PostgresStatsReader
+	*PostgresSequence
+	*PostgresSlugPool
+	*PostgresAPIKeyStore
 }
 
 // Проверка реализации интерфейса
-var _ repository.URLRepository = (*PostgresRepo)(nil)
+var _ repository.Store = (*PostgresRepo)(nil)
 
 // NewRepo создаёт единый PostgresRepo
 func NewRepo(db DBExecutor, log logger.Logger) *PostgresRepo {
 	return &PostgresRepo{
-		PostgresReader: NewPostgresReader(db, log),
-		PostgresWriter: NewPostgresWriter(db, log),
+		PostgresReader:      NewPostgresReader(db, log),
+		PostgresWriter:      NewPostgresWriter(db, log),
+		PostgresClickWriter: NewPostgresClickWriter(db, log),
+		PostgresStatsReader: NewPostgresStatsReader(db, log),
+		PostgresSequence:    NewPostgresSequence(db, log),
+		PostgresSlugPool:    NewPostgresSlugPool(db, log),
+		PostgresAPIKeyStore: NewPostgresAPIKeyStore(db, log),
 	}
 }
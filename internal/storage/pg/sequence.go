@@ -0,0 +1,34 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+type PostgresSequence struct {
+	db     DBExecutor
+	logger logger.Logger
+}
+
+func NewPostgresSequence(db DBExecutor, l logger.Logger) *PostgresSequence {
+	return &PostgresSequence{
+		db:     db,
+		logger: l,
+	}
+}
+
+var _ repository.SlugSequence = (*PostgresSequence)(nil)
+
+const nextSlugIDQuery = `SELECT nextval('slug_seq')`
+
+func (s *PostgresSequence) NextSlugID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.db.QueryRow(ctx, nextSlugIDQuery).Scan(&id); err != nil {
+		s.logger.Error("failed to get next slug id", err, nil)
+		return 0, fmt.Errorf("next slug id: %w", err)
+	}
+	return id, nil
+}
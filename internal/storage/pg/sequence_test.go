@@ -0,0 +1,50 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestNextSlugID(t *testing.T) {
+	t.Run("возвращает следующее значение последовательности", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+		loggerMock := &mocks.MockLogger{}
+
+		rowMock.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			dest := args.Get(0).([]any)
+			*(dest[0].(*int64)) = 7
+		}).Return(nil).Once()
+		dbMock.On("QueryRow", mock.Anything, nextSlugIDQuery, mock.Anything).Return(rowMock).Once()
+
+		s := NewPostgresSequence(dbMock, loggerMock)
+		id, err := s.NextSlugID(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), id)
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("ошибка запроса логируется", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		rowMock := &mocks.MockRow{}
+		loggerMock := &mocks.MockLogger{}
+		dbErr := errors.New("sequence missing")
+
+		rowMock.On("Scan", mock.Anything).Return(dbErr).Once()
+		dbMock.On("QueryRow", mock.Anything, nextSlugIDQuery, mock.Anything).Return(rowMock).Once()
+		loggerMock.On("Error", "failed to get next slug id", dbErr, mock.Anything).Once()
+
+		s := NewPostgresSequence(dbMock, loggerMock)
+		_, err := s.NextSlugID(context.Background())
+
+		assert.ErrorIs(t, err, dbErr)
+		loggerMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,74 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+type PostgresSlugPool struct {
+	db     DBExecutor
+	logger logger.Logger
+}
+
+func NewPostgresSlugPool(db DBExecutor, l logger.Logger) *PostgresSlugPool {
+	return &PostgresSlugPool{
+		db:     db,
+		logger: l,
+	}
+}
+
+var _ repository.SlugPoolStore = (*PostgresSlugPool)(nil)
+
+const (
+	// Кандидаты, уже занятые ссылками или лежащие в пуле, молча пропускаются
+	addPooledSlugsQuery = `INSERT INTO slug_pool (slug)
+		SELECT s FROM unnest($1::text[]) AS s
+		WHERE NOT EXISTS (SELECT 1 FROM urls WHERE urls.slug = s)
+		ON CONFLICT (slug) DO NOTHING`
+
+	// SKIP LOCKED позволяет нескольким инстансам разбирать пул одновременно без пересечений
+	takePooledSlugsQuery = `DELETE FROM slug_pool
+		WHERE slug IN (SELECT slug FROM slug_pool LIMIT $1 FOR UPDATE SKIP LOCKED)
+		RETURNING slug`
+)
+
+func (p *PostgresSlugPool) AddPooledSlugs(ctx context.Context, slugs []string) (int, error) {
+	if len(slugs) == 0 {
+		return 0, nil
+	}
+
+	tag, err := p.db.Exec(ctx, addPooledSlugsQuery, slugs)
+	if err != nil {
+		p.logger.Error("failed to add slugs to pool", err, map[string]interface{}{
+			"count": len(slugs),
+		})
+		return 0, fmt.Errorf("add pooled slugs: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (p *PostgresSlugPool) TakePooledSlugs(ctx context.Context, n int) ([]string, error) {
+	rows, err := p.db.Query(ctx, takePooledSlugsQuery, n)
+	if err != nil {
+		p.logger.Error("failed to take slugs from pool", err, nil)
+		return nil, fmt.Errorf("take pooled slugs: %w", err)
+	}
+	defer rows.Close()
+
+	slugs := make([]string, 0, n)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, fmt.Errorf("take pooled slugs: %w", err)
+		}
+		slugs = append(slugs, slug)
+	}
+	if err := rows.Err(); err != nil {
+		p.logger.Error("failed to take slugs from pool", err, nil)
+		return nil, fmt.Errorf("take pooled slugs: %w", err)
+	}
+	return slugs, nil
+}
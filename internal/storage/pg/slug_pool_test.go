@@ -0,0 +1,60 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestAddPooledSlugs(t *testing.T) {
+	dbMock := &mocks.MockDBExecutor{}
+	loggerMock := &mocks.MockLogger{}
+
+	dbMock.On("Exec", mock.Anything, addPooledSlugsQuery, []interface{}{[]string{"a", "b", "c"}}).
+		Return(pgconn.NewCommandTag("INSERT 0 2"), nil).Once()
+
+	p := NewPostgresSlugPool(dbMock, loggerMock)
+	added, err := p.AddPooledSlugs(context.Background(), []string{"a", "b", "c"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+	dbMock.AssertExpectations(t)
+}
+
+func TestTakePooledSlugs(t *testing.T) {
+	t.Run("возвращает удалённые из пула slug", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+		rows := &mocks.MockRows{Data: [][]any{{"a"}, {"b"}}}
+
+		dbMock.On("Query", mock.Anything, takePooledSlugsQuery, []interface{}{5}).Return(rows, nil).Once()
+
+		p := NewPostgresSlugPool(dbMock, loggerMock)
+		slugs, err := p.TakePooledSlugs(context.Background(), 5)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, slugs)
+		assert.True(t, rows.Closed)
+	})
+
+	t.Run("ошибка запроса логируется", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+		dbErr := errors.New("db down")
+
+		dbMock.On("Query", mock.Anything, takePooledSlugsQuery, mock.Anything).Return(nil, dbErr).Once()
+		loggerMock.On("Error", "failed to take slugs from pool", dbErr, mock.Anything).Once()
+
+		p := NewPostgresSlugPool(dbMock, loggerMock)
+		_, err := p.TakePooledSlugs(context.Background(), 5)
+
+		assert.ErrorIs(t, err, dbErr)
+		loggerMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,117 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+type PostgresStatsReader struct {
+	db     DBExecutor
+	logger logger.Logger
+}
+
+func NewPostgresStatsReader(db DBExecutor, l logger.Logger) *PostgresStatsReader {
+	return &PostgresStatsReader{
+		db:     db,
+		logger: l,
+	}
+}
+
+var _ repository.StatsReader = (*PostgresStatsReader)(nil)
+
+const (
+	statsTotalsQuery = `SELECT COUNT(*), COUNT(DISTINCT ip_hash) FROM clicks
+		WHERE slug = $1 AND clicked_at >= $2 AND clicked_at < $3`
+
+	// $4 — 'hour' или 'day'; корзины считаем в UTC
+	statsSeriesQuery = `SELECT date_trunc($4, clicked_at AT TIME ZONE 'UTC') AS bucket,
+		COUNT(*), COUNT(DISTINCT ip_hash) FROM clicks
+		WHERE slug = $1 AND clicked_at >= $2 AND clicked_at < $3
+		GROUP BY bucket ORDER BY bucket`
+
+	statsTopReferrersQuery = `SELECT referrer, COUNT(*) AS clicks FROM clicks
+		WHERE slug = $1 AND clicked_at >= $2 AND clicked_at < $3 AND referrer <> ''
+		GROUP BY referrer ORDER BY clicks DESC, referrer LIMIT $4`
+
+	statsTopUserAgentsQuery = `SELECT ua_family, COUNT(*) AS clicks FROM clicks
+		WHERE slug = $1 AND clicked_at >= $2 AND clicked_at < $3
+		GROUP BY ua_family ORDER BY clicks DESC, ua_family LIMIT $4`
+
+	// Варианты не ограничиваются limit: для сравнения A/B нужны все, а их немного
+	statsVariantsQuery = `SELECT variant, COUNT(*) AS clicks FROM clicks
+		WHERE slug = $1 AND clicked_at >= $2 AND clicked_at < $3 AND variant <> ''
+		GROUP BY variant ORDER BY clicks DESC, variant`
+)
+
+func (r *PostgresStatsReader) LinkStats(ctx context.Context, slug string, q model.StatsQuery) (*model.LinkStats, error) {
+	stats := &model.LinkStats{}
+
+	err := r.db.QueryRow(ctx, statsTotalsQuery, slug, q.From, q.To).Scan(&stats.TotalClicks, &stats.UniqueVisitors)
+	if err != nil {
+		return nil, r.fail("totals", slug, err)
+	}
+
+	if stats.Series, err = r.series(ctx, slug, q); err != nil {
+		return nil, r.fail("series", slug, err)
+	}
+	if stats.TopReferrers, err = r.counts(ctx, statsTopReferrersQuery, slug, q.From, q.To, q.Limit); err != nil {
+		return nil, r.fail("top referrers", slug, err)
+	}
+	if stats.TopUserAgents, err = r.counts(ctx, statsTopUserAgentsQuery, slug, q.From, q.To, q.Limit); err != nil {
+		return nil, r.fail("top user agents", slug, err)
+	}
+	if stats.Variants, err = r.counts(ctx, statsVariantsQuery, slug, q.From, q.To); err != nil {
+		return nil, r.fail("variants", slug, err)
+	}
+
+	return stats, nil
+}
+
+func (r *PostgresStatsReader) series(ctx context.Context, slug string, q model.StatsQuery) ([]model.StatsBucket, error) {
+	rows, err := r.db.Query(ctx, statsSeriesQuery, slug, q.From, q.To, q.Interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []model.StatsBucket
+	for rows.Next() {
+		var b model.StatsBucket
+		if err := rows.Scan(&b.Start, &b.Clicks, &b.UniqueVisitors); err != nil {
+			return nil, err
+		}
+		series = append(series, b)
+	}
+	return series, rows.Err()
+}
+
+// counts выполняет запрос, возвращающий пары «значение — число переходов».
+func (r *PostgresStatsReader) counts(ctx context.Context, query string, args ...interface{}) ([]model.StatsCount, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var top []model.StatsCount
+	for rows.Next() {
+		var c model.StatsCount
+		if err := rows.Scan(&c.Value, &c.Clicks); err != nil {
+			return nil, err
+		}
+		top = append(top, c)
+	}
+	return top, rows.Err()
+}
+
+func (r *PostgresStatsReader) fail(stage, slug string, err error) error {
+	r.logger.Error("failed to query link stats", err, map[string]interface{}{
+		"slug":  slug,
+		"stage": stage,
+	})
+	return fmt.Errorf("link stats %s: %w", stage, err)
+}
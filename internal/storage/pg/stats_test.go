@@ -0,0 +1,73 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+)
+
+func TestLinkStats(t *testing.T) {
+	from := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := model.StatsQuery{From: from, To: from.Add(48 * time.Hour), Interval: model.StatsIntervalDay, Limit: 5}
+
+	t.Run("собирает итоги, ряд и топы", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		row := &mocks.MockRow{}
+		row.On("Scan", mock.Anything).Run(func(args mock.Arguments) {
+			dest := args.Get(0).([]interface{})
+			*dest[0].(*int64) = 3
+			*dest[1].(*int64) = 2
+		}).Return(nil).Once()
+
+		dbMock.On("QueryRow", mock.Anything, statsTotalsQuery, []interface{}{"abc", q.From, q.To}).Return(row).Once()
+		dbMock.On("Query", mock.Anything, statsSeriesQuery, []interface{}{"abc", q.From, q.To, q.Interval}).
+			Return(&mocks.MockRows{Data: [][]any{{from, int64(3), int64(2)}}}, nil).Once()
+		dbMock.On("Query", mock.Anything, statsTopReferrersQuery, []interface{}{"abc", q.From, q.To, q.Limit}).
+			Return(&mocks.MockRows{Data: [][]any{{"https://t.me", int64(2)}}}, nil).Once()
+		dbMock.On("Query", mock.Anything, statsTopUserAgentsQuery, []interface{}{"abc", q.From, q.To, q.Limit}).
+			Return(&mocks.MockRows{Data: [][]any{{"Chrome", int64(3)}}}, nil).Once()
+		dbMock.On("Query", mock.Anything, statsVariantsQuery, []interface{}{"abc", q.From, q.To}).
+			Return(&mocks.MockRows{Data: [][]any{{"a", int64(2)}, {"b", int64(1)}}}, nil).Once()
+
+		r := NewPostgresStatsReader(dbMock, loggerMock)
+		stats, err := r.LinkStats(context.Background(), "abc", q)
+
+		require.NoError(t, err)
+		assert.Equal(t, &model.LinkStats{
+			TotalClicks:    3,
+			UniqueVisitors: 2,
+			Series:         []model.StatsBucket{{Start: from, Clicks: 3, UniqueVisitors: 2}},
+			TopReferrers:   []model.StatsCount{{Value: "https://t.me", Clicks: 2}},
+			TopUserAgents:  []model.StatsCount{{Value: "Chrome", Clicks: 3}},
+			Variants:       []model.StatsCount{{Value: "a", Clicks: 2}, {Value: "b", Clicks: 1}},
+		}, stats)
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("ошибка запроса логируется и оборачивается", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+		dbErr := errors.New("db down")
+
+		row := &mocks.MockRow{}
+		row.On("Scan", mock.Anything).Return(dbErr).Once()
+		dbMock.On("QueryRow", mock.Anything, statsTotalsQuery, mock.Anything).Return(row).Once()
+		loggerMock.On("Error", "failed to query link stats", dbErr, mock.Anything).Once()
+
+		r := NewPostgresStatsReader(dbMock, loggerMock)
+		_, err := r.LinkStats(context.Background(), "abc", q)
+
+		assert.ErrorIs(t, err, dbErr)
+		loggerMock.AssertExpectations(t)
+	})
+}
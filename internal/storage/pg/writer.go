@@ -24,12 +24,48 @@ func NewPostgresWriter(db DBExecutor, l logger.Logger) *PostgresWriter {
 
 var _ repository.URLWriter = (*PostgresWriter)(nil)
 
+// Ссылка и её правила перехода пишутся одним запросом: правила разворачиваются из массивов
+// через unnest, их порядок — номер элемента
+const (
+	createLinkQuery = `WITH link AS (
+			INSERT INTO urls (slug, url, status, created_at, expires_at, canonical_url, owner_id, redirect_type,
+				query_passthrough, path_passthrough, utm, utm_template, targets, sticky_targets)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING slug
+		)
+		INSERT INTO link_rules (slug, position, os, device, bot, url)
+		SELECT link.slug, r.position, r.os, r.device, r.bot, r.url
+		FROM link, unnest($15::text[], $16::text[], $17::boolean[], $18::text[])
+			WITH ORDINALITY AS r(os, device, bot, url, position)`
+	updateLinkQuery = `WITH cleared AS (
+			DELETE FROM link_rules WHERE slug = $1
+		), added AS (
+			INSERT INTO link_rules (slug, position, os, device, bot, url)
+			SELECT $1, r.position, r.os, r.device, r.bot, r.url
+			FROM unnest($13::text[], $14::text[], $15::boolean[], $16::text[])
+				WITH ORDINALITY AS r(os, device, bot, url, position)
+			WHERE EXISTS (SELECT 1 FROM urls WHERE slug = $1)
+		)
+		UPDATE urls SET url = $2, status = $3, expires_at = $4, canonical_url = $5, redirect_type = $6,
+			query_passthrough = $7, path_passthrough = $8, utm = $9, utm_template = $10,
+			targets = $11, sticky_targets = $12
+		WHERE slug = $1`
+	deleteLinkQuery = `DELETE FROM urls WHERE slug = $1`
+)
+
 func (w *PostgresWriter) Create(ctx context.Context, link *model.Link) error {
-	const query = `INSERT INTO urls (slug, url, created_at) VALUES ($1, $2, $3)`
-	_, err := w.db.Exec(ctx, query, link.Slug, link.URL, link.CreatedAt)
+	if link.Status == "" {
+		link.Status = model.LinkStatusActive
+	}
+
+	os, device, bot, url := ruleColumns(link.Rules)
+	_, err := w.db.Exec(ctx, createLinkQuery,
+		link.Slug, link.URL, link.Status, link.CreatedAt, link.ExpiresAt, link.DedupeKey(), link.OwnerID, link.RedirectType,
+		link.QueryPassthrough, link.PathPassthrough, link.UTM, link.UTMTemplate, link.Targets, link.StickyTargets,
+		os, device, bot, url)
 	if err != nil {
 
-		// Обработка уникального конфликта (slug или url)
+		// Обработка уникального конфликта (slug)
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
 			return repository.ErrAlreadyExists
@@ -43,3 +79,49 @@ func (w *PostgresWriter) Create(ctx context.Context, link *model.Link) error {
 	}
 	return nil
 }
+
+func (w *PostgresWriter) Update(ctx context.Context, link *model.Link) error {
+	os, device, bot, url := ruleColumns(link.Rules)
+	tag, err := w.db.Exec(ctx, updateLinkQuery, link.Slug, link.URL, link.Status, link.ExpiresAt, link.DedupeKey(),
+		link.RedirectType, link.QueryPassthrough, link.PathPassthrough, link.UTM, link.UTMTemplate,
+		link.Targets, link.StickyTargets, os, device, bot, url)
+	if err != nil {
+		w.logger.Error("failed to update link", err, map[string]interface{}{
+			"slug": link.Slug,
+		})
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (w *PostgresWriter) Delete(ctx context.Context, slug string) error {
+	tag, err := w.db.Exec(ctx, deleteLinkQuery, slug)
+	if err != nil {
+		w.logger.Error("failed to delete link", err, map[string]interface{}{
+			"slug": slug,
+		})
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ruleColumns раскладывает правила перехода по массивам колонок для unnest.
+func ruleColumns(rules []model.LinkRule) (os, device []string, bot []*bool, url []string) {
+	os = make([]string, len(rules))
+	device = make([]string, len(rules))
+	bot = make([]*bool, len(rules))
+	url = make([]string, len(rules))
+	for i, r := range rules {
+		os[i] = r.OS
+		device[i] = r.Device
+		bot[i] = r.Bot
+		url[i] = r.URL
+	}
+	return os, device, bot, url
+}
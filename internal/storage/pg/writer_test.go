@@ -29,8 +29,9 @@ func TestCreate(t *testing.T) {
 		// Программируем Exec так, чтобы он возвращал успех (нет ошибки).
 		// При успехе обычно возвращается какой-то CommandTag, например "INSERT 1".
 		dbMock.On("Exec", mock.Anything,
-			"INSERT INTO urls (slug, url, created_at) VALUES ($1, $2, $3)",
-			[]interface{}{slug, url, createdAt},
+			createLinkQuery,
+			[]interface{}{slug, url, model.LinkStatusActive, createdAt, (*time.Time)(nil), url, "", 0, "", false, (*model.UTM)(nil), "",
+				[]model.LinkTarget(nil), false, []string{}, []string{}, []*bool{}, []string{}},
 		).Return(pgconn.NewCommandTag("INSERT 1"), nil).Once()
 
 		// Мы НЕ ожидаем вызова loggerMock.Error(...) в случае успеха.
@@ -67,8 +68,9 @@ func TestCreate(t *testing.T) {
 			Code: "23505", // уникальный конфликт
 		}
 		dbMock.On("Exec", mock.Anything,
-			"INSERT INTO urls (slug, url, created_at) VALUES ($1, $2, $3)",
-			[]interface{}{slug, url, createdAt},
+			createLinkQuery,
+			[]interface{}{slug, url, model.LinkStatusActive, createdAt, (*time.Time)(nil), url, "", 0, "", false, (*model.UTM)(nil), "",
+				[]model.LinkTarget(nil), false, []string{}, []string{}, []*bool{}, []string{}},
 		).Return(pgconn.NewCommandTag(""), pgErr).Once()
 
 		// В случае "23505" метод Create должен вернуть repository.ErrAlreadyExists
@@ -100,8 +102,9 @@ func TestCreate(t *testing.T) {
 		createdAt := time.Now()
 
 		dbMock.On("Exec", mock.Anything,
-			"INSERT INTO urls (slug, url, created_at) VALUES ($1, $2, $3)",
-			[]interface{}{slug, url, createdAt},
+			createLinkQuery,
+			[]interface{}{slug, url, model.LinkStatusActive, createdAt, (*time.Time)(nil), url, "", 0, "", false, (*model.UTM)(nil), "",
+				[]model.LinkTarget(nil), false, []string{}, []string{}, []*bool{}, []string{}},
 		).Return(pgconn.NewCommandTag(""), errors.New("db failure")).Once()
 
 		// В таком случае код должен вызвать logger.Error(...)
@@ -134,12 +137,12 @@ func TestCreate(t *testing.T) {
 		}
 		dbMock.On("Exec",
 			mock.Anything,
-			"INSERT INTO urls (slug, url, created_at) VALUES ($1, $2, $3)",
+			createLinkQuery,
 			mock.MatchedBy(func(args []interface{}) bool {
-				return len(args) == 3 &&
+				return len(args) == 18 &&
 					args[0] == "" && // slug
 					args[1] == "https://gaps.com" // url
-				// args[2] — неважно, пропускаем
+				// остальные аргументы неважны, пропускаем
 			}),
 		).Return(pgconn.NewCommandTag(""), pgErr).Once()
 
@@ -160,3 +163,94 @@ func TestCreate(t *testing.T) {
 		loggerMock.AssertExpectations(t)
 	})
 }
+
+func TestUpdate(t *testing.T) {
+	t.Run("успешное обновление ссылки", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+		human := false
+
+		dbMock.On("Exec", mock.Anything, updateLinkQuery,
+			[]interface{}{"my-slug", "https://new.example.com", model.LinkStatusDisabled, (*time.Time)(nil), "https://new.example.com",
+				model.RedirectTemporaryRedirect, model.QueryPassthroughKeep, true, (*model.UTM)(nil), "newsletter",
+				[]model.LinkTarget(nil), false, []string{"ios", ""}, []string{"", "desktop"}, []*bool{nil, &human}, []string{"https://apps.apple.com/app", "https://example.com/web"}},
+		).Return(pgconn.NewCommandTag("UPDATE 1"), nil).Once()
+
+		writer := &PostgresWriter{db: dbMock, logger: loggerMock}
+		err := writer.Update(context.Background(), &model.Link{
+			Slug:         "my-slug",
+			URL:          "https://new.example.com",
+			Status:       model.LinkStatusDisabled,
+			RedirectType: model.RedirectTemporaryRedirect,
+
+			QueryPassthrough: model.QueryPassthroughKeep,
+			PathPassthrough:  true,
+			UTMTemplate:      "newsletter",
+			Rules: []model.LinkRule{
+				{OS: "ios", URL: "https://apps.apple.com/app"},
+				{Device: "desktop", Bot: &human, URL: "https://example.com/web"},
+			},
+		})
+		assert.NoError(t, err)
+
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("ошибка: ссылка не найдена", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		dbMock.On("Exec", mock.Anything, updateLinkQuery, mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil).Once()
+
+		writer := &PostgresWriter{db: dbMock, logger: loggerMock}
+		err := writer.Update(context.Background(), &model.Link{Slug: "missing"})
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		dbMock.AssertExpectations(t)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("успешное удаление ссылки", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		dbMock.On("Exec", mock.Anything, deleteLinkQuery, []interface{}{"my-slug"}).
+			Return(pgconn.NewCommandTag("DELETE 1"), nil).Once()
+
+		writer := &PostgresWriter{db: dbMock, logger: loggerMock}
+		assert.NoError(t, writer.Delete(context.Background(), "my-slug"))
+
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("ошибка: ссылка не найдена", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		dbMock.On("Exec", mock.Anything, deleteLinkQuery, []interface{}{"missing"}).
+			Return(pgconn.NewCommandTag("DELETE 0"), nil).Once()
+
+		writer := &PostgresWriter{db: dbMock, logger: loggerMock}
+		assert.ErrorIs(t, writer.Delete(context.Background(), "missing"), repository.ErrNotFound)
+
+		dbMock.AssertExpectations(t)
+	})
+
+	t.Run("ошибка БД", func(t *testing.T) {
+		dbMock := &mocks.MockDBExecutor{}
+		loggerMock := &mocks.MockLogger{}
+
+		dbErr := errors.New("db failure")
+		dbMock.On("Exec", mock.Anything, deleteLinkQuery, []interface{}{"my-slug"}).
+			Return(pgconn.NewCommandTag(""), dbErr).Once()
+		loggerMock.On("Error", "failed to delete link", dbErr, mock.Anything).Once()
+
+		writer := &PostgresWriter{db: dbMock, logger: loggerMock}
+		assert.ErrorIs(t, writer.Delete(context.Background(), "my-slug"), dbErr)
+
+		dbMock.AssertExpectations(t)
+		loggerMock.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Thoustick/SlugKiller/infrastructure/db"
+	"github.com/Thoustick/SlugKiller/internal/storage/pg"
+)
+
+// PostgresStore — репозиторий PostgreSQL вместе с подключением, которым он владеет.
+type PostgresStore struct {
+	*pg.PostgresRepo
+	db *db.PG
+}
+
+func newPostgresStore(conn *db.PG) *PostgresStore {
+	return &PostgresStore{
+		PostgresRepo: pg.NewRepo(conn.Pool, conn.Logger),
+		db:           conn,
+	}
+}
+
+// PoolStat возвращает статистику пула соединений.
+func (s *PostgresStore) PoolStat() *pgxpool.Stat {
+	return s.db.Pool.Stat()
+}
+
+// Ping проверяет, что база отвечает.
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	return s.db.Pool.Ping(ctx)
+}
+
+// Close закрывает пул соединений, дождавшись возврата занятых соединений.
+func (s *PostgresStore) Close() error {
+	s.db.Close()
+	return nil
+}
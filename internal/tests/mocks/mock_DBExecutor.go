@@ -22,6 +22,13 @@ func (m *MockDBExecutor) QueryRow(ctx context.Context, sql string, args ...inter
 	return row
 }
 
+func (m *MockDBExecutor) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ret := m.Called(ctx, sql, args)
+	rows, _ := ret.Get(0).(pgx.Rows)
+	err, _ := ret.Get(1).(error)
+	return rows, err
+}
+
 func (m *MockDBExecutor) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
 	ret := m.Called(ctx, sql, args)
 	cmdTag, _ := ret.Get(0).(pgconn.CommandTag)
@@ -11,8 +11,8 @@ type MockURLRepository struct {
 	mock.Mock
 }
 
-func (m *MockURLRepository) GetByOriginalURL(ctx context.Context, original string) (*model.Link, error) {
-	args := m.Called(ctx, original)
+func (m *MockURLRepository) GetByCanonicalURL(ctx context.Context, ownerID, key string) (*model.Link, error) {
+	args := m.Called(ctx, ownerID, key)
 	link := args.Get(0)
 	if link == nil {
 		return nil, args.Error(1)
@@ -38,3 +38,8 @@ func (m *MockURLRepository) Delete(ctx context.Context, slug string) error {
 	args := m.Called(ctx, slug)
 	return args.Error(0)
 }
+
+func (m *MockURLRepository) Update(ctx context.Context, url *model.Link) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
@@ -3,6 +3,8 @@ package mocks
 import (
 	"context"
 
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/service"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -10,12 +12,39 @@ type MockURLService struct {
 	mock.Mock
 }
 
-func (m *MockURLService) Shorten(ctx context.Context, originalURL string) (string, error) {
-	args := m.Called(ctx, originalURL)
+func (m *MockURLService) Shorten(ctx context.Context, originalURL string, opts service.ShortenOptions) (string, error) {
+	args := m.Called(ctx, originalURL, opts)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockURLService) Resolve(ctx context.Context, slug string) (string, error) {
-	args := m.Called(ctx, slug)
-	return args.String(0), args.Error(1)
+func (m *MockURLService) Resolve(ctx context.Context, slug string, v service.Visitor) (*service.Redirect, error) {
+	args := m.Called(ctx, slug, v)
+	r := args.Get(0)
+	if r == nil {
+		return nil, args.Error(1)
+	}
+	return r.(*service.Redirect), args.Error(1)
+}
+
+func (m *MockURLService) GetLink(ctx context.Context, ownerID, slug string) (*model.Link, error) {
+	args := m.Called(ctx, ownerID, slug)
+	link := args.Get(0)
+	if link == nil {
+		return nil, args.Error(1)
+	}
+	return link.(*model.Link), args.Error(1)
+}
+
+func (m *MockURLService) UpdateLink(ctx context.Context, ownerID, slug string, upd service.LinkUpdate) (*model.Link, error) {
+	args := m.Called(ctx, ownerID, slug, upd)
+	link := args.Get(0)
+	if link == nil {
+		return nil, args.Error(1)
+	}
+	return link.(*model.Link), args.Error(1)
+}
+
+func (m *MockURLService) DeleteLink(ctx context.Context, ownerID, slug string) error {
+	args := m.Called(ctx, ownerID, slug)
+	return args.Error(0)
 }
@@ -20,3 +20,8 @@ func (m *MockCache) SetNX(ctx context.Context, slug, url string, ttl time.Durati
 	args := m.Called(ctx, slug, url, ttl)
 	return args.Error(0)
 }
+
+func (m *MockCache) Delete(ctx context.Context, slug string) error {
+	args := m.Called(ctx, slug)
+	return args.Error(0)
+}
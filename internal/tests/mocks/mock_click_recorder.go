@@ -0,0 +1,14 @@
+package mocks
+
+import (
+	"github.com/Thoustick/SlugKiller/internal/analytics"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockClickRecorder struct {
+	mock.Mock
+}
+
+func (m *MockClickRecorder) Record(event analytics.ClickEvent) {
+	m.Called(event)
+}
@@ -0,0 +1,55 @@
+package mocks
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MockRows — реализация pgx.Rows поверх заранее заданных строк.
+// Scan раскладывает значения строки по указателям dest в том же порядке.
+type MockRows struct {
+	Data   [][]any
+	Error  error // возвращается из Err после перебора
+	Closed bool
+
+	pos int
+}
+
+var _ pgx.Rows = (*MockRows)(nil)
+
+func (r *MockRows) Close() { r.Closed = true }
+
+func (r *MockRows) Err() error { return r.Error }
+
+func (r *MockRows) CommandTag() pgconn.CommandTag { return pgconn.NewCommandTag("SELECT") }
+
+func (r *MockRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+
+func (r *MockRows) Next() bool {
+	if r.pos >= len(r.Data) {
+		r.Closed = true
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *MockRows) Scan(dest ...any) error {
+	row := r.Data[r.pos-1]
+	if len(row) != len(dest) {
+		return fmt.Errorf("mock rows: %d values for %d destinations", len(row), len(dest))
+	}
+	for i, v := range row {
+		reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+	}
+	return nil
+}
+
+func (r *MockRows) Values() ([]any, error) { return r.Data[r.pos-1], nil }
+
+func (r *MockRows) RawValues() [][]byte { return nil }
+
+func (r *MockRows) Conn() *pgx.Conn { return nil }
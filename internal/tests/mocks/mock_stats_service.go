@@ -0,0 +1,21 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatsService struct {
+	mock.Mock
+}
+
+func (m *MockStatsService) LinkStats(ctx context.Context, ownerID, slug string, q model.StatsQuery) (*model.LinkStats, error) {
+	args := m.Called(ctx, ownerID, slug, q)
+	stats := args.Get(0)
+	if stats == nil {
+		return nil, args.Error(1)
+	}
+	return stats.(*model.LinkStats), args.Error(1)
+}
@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/Thoustick/SlugKiller/internal/model"
+)
+
+// MockStore — мок repository.Store: ссылки от MockURLRepository плюс клики, статистика, счётчик, пул slug и API-ключи.
+type MockStore struct {
+	MockURLRepository
+}
+
+func (m *MockStore) SaveClicks(ctx context.Context, clicks []model.Click) error {
+	args := m.Called(ctx, clicks)
+	return args.Error(0)
+}
+
+func (m *MockStore) LinkStats(ctx context.Context, slug string, q model.StatsQuery) (*model.LinkStats, error) {
+	args := m.Called(ctx, slug, q)
+	stats := args.Get(0)
+	if stats == nil {
+		return nil, args.Error(1)
+	}
+	return stats.(*model.LinkStats), args.Error(1)
+}
+
+func (m *MockStore) NextSlugID(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockStore) AddPooledSlugs(ctx context.Context, slugs []string) (int, error) {
+	args := m.Called(ctx, slugs)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStore) TakePooledSlugs(ctx context.Context, n int) ([]string, error) {
+	args := m.Called(ctx, n)
+	slugs, _ := args.Get(0).([]string)
+	return slugs, args.Error(1)
+}
+
+func (m *MockStore) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	args := m.Called(ctx, hash)
+	key, _ := args.Get(0).(*model.APIKey)
+	return key, args.Error(1)
+}
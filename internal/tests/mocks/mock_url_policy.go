@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockURLPolicy struct {
+	mock.Mock
+}
+
+func (m *MockURLPolicy) Check(ctx context.Context, rawURL string) error {
+	args := m.Called(ctx, rawURL)
+	return args.Error(0)
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PgxTracer открывает спан на каждый запрос pgx. Подключается через ConnConfig.Tracer,
+// поэтому покрывает все запросы ридера, райтера и остальных частей хранилища.
+type PgxTracer struct{}
+
+var _ pgx.QueryTracer = PgxTracer{}
+
+func (PgxTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := sqlOperation(data.SQL)
+	ctx, _ = otel.Tracer(instrumentationName).Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			// В запросах только плейсхолдеры, значения параметров в спан не попадают
+			attribute.String("db.query.text", data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (PgxTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	// pgx.ErrNoRows — штатный ответ «нет записи», а не сбой базы
+	if errors.Is(data.Err, pgx.ErrNoRows) {
+		span.End()
+		return
+	}
+	End(span, data.Err)
+}
+
+// sqlOperation возвращает первое слово запроса (SELECT, INSERT, ...).
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+// Экспортёры спанов — значения TRACING_EXPORTER
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// serviceName — имя сервиса в ресурсах трассировки.
+const serviceName = "slugkiller"
+
+// instrumentationName — имя трейсера, под которым сервис пишет свои спаны.
+const instrumentationName = "github.com/Thoustick/SlugKiller"
+
+// Setup настраивает глобальный TracerProvider и W3C-пропагатор trace context. Возвращает функцию,
+// которая дописывает накопленные спаны и останавливает экспортёр. При TRACING_EXPORTER=none
+// спаны не записываются, но контекст из входящих заголовков всё равно передаётся дальше.
+func Setup(ctx context.Context, cfg *config.Config, log logger.Logger) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.TracingExporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		// Адрес коллектора и заголовки берутся из стандартных OTEL_EXPORTER_OTLP_* переменных
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("invalid tracing exporter: %s", cfg.TracingExporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.TracingExporter, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Info("Tracing enabled", map[string]interface{}{
+		"exporter":     cfg.TracingExporter,
+		"sample_ratio": cfg.TracingSampleRatio,
+	})
+	return provider.Shutdown, nil
+}
+
+// Start открывает дочерний спан от спана в ctx. Трейсер берётся из глобального провайдера
+// при каждом вызове, поэтому Setup (и подмена провайдера в тестах) действует сразу везде.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End закрывает спан, отмечая его ошибкой, если err не nil. Ожидаемые исходы
+// (промах кэша, отсутствующая ссылка) вызывающий передаёт как nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,150 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/cache"
+	"github.com/Thoustick/SlugKiller/internal/handler"
+	"github.com/Thoustick/SlugKiller/internal/model"
+	"github.com/Thoustick/SlugKiller/internal/repository"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+	"github.com/Thoustick/SlugKiller/internal/tracing"
+)
+
+// setupTestTracing подменяет глобальный провайдер на запись спанов в память.
+func setupTestTracing(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return exporter
+}
+
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	return byName
+}
+
+func TestResolve_SpansAcrossLayers(t *testing.T) {
+	exporter := setupTestTracing(t)
+	gin.SetMode(gin.TestMode)
+
+	repo := new(mocks.MockURLRepository)
+	c := new(mocks.MockCache)
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+	log.On("Debug", mock.Anything, mock.Anything).Maybe()
+
+	c.On("Get", mock.Anything, "abc").Return("", cache.ErrCacheMiss).Once()
+	repo.On("GetBySlug", mock.Anything, "abc").Return(&model.Link{Slug: "abc", URL: "https://example.com/"}, nil).Once()
+	c.On("SetNX", mock.Anything, "abc", "https://example.com/", mock.Anything).Return(nil).Once()
+
+	svc := service.NewURLService(repo, log, c, &config.Config{MaxAttempts: 1}, nil, nil)
+	r := gin.New()
+	r.Use(tracing.Middleware())
+	handler.NewHandler(svc, nil, nil, log).RegisterRoutes(r)
+
+	// Входящий W3C traceparent продолжает трассу вызывающего
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const parentID = "00f067aa0ba902b7"
+	req := httptest.NewRequest(http.MethodGet, "/abc", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusFound, w.Code)
+
+	spans := spansByName(exporter.GetSpans())
+	require.Contains(t, spans, "GET /:slug")
+	server := spans["GET /:slug"]
+	assert.Equal(t, traceID, server.SpanContext.TraceID().String())
+	assert.Equal(t, parentID, server.Parent.SpanID().String())
+
+	// Каждый слой — дочерний спан предыдущего
+	chain := []string{"GET /:slug", "Handler.ResolveURL", "urlService.Resolve", "urlService.resolveFromDB"}
+	for i := 1; i < len(chain); i++ {
+		require.Contains(t, spans, chain[i])
+		assert.Equal(t, spans[chain[i-1]].SpanContext.SpanID(), spans[chain[i]].Parent.SpanID(), chain[i])
+		assert.Equal(t, traceID, spans[chain[i]].SpanContext.TraceID().String(), chain[i])
+	}
+}
+
+func TestResolve_NotFoundIsNotSpanError(t *testing.T) {
+	exporter := setupTestTracing(t)
+
+	repo := new(mocks.MockURLRepository)
+	c := new(mocks.MockCache)
+	log := new(mocks.MockLogger)
+	log.On("Warn", mock.Anything, mock.Anything).Maybe()
+
+	c.On("Get", mock.Anything, "nope").Return("", cache.ErrCacheMiss).Once()
+	c.On("Get", mock.Anything, "boom").Return("", cache.ErrCacheMiss).Once()
+	repo.On("GetBySlug", mock.Anything, "nope").Return(nil, repository.ErrNotFound).Once()
+	repo.On("GetBySlug", mock.Anything, "boom").Return(nil, errors.New("connection reset")).Once()
+	log.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	svc := service.NewURLService(repo, log, c, &config.Config{}, nil, nil)
+	_, _ = svc.Resolve(context.Background(), "nope", service.Visitor{})
+	assert.Equal(t, codes.Unset, spansByName(exporter.GetSpans())["urlService.Resolve"].Status.Code)
+
+	exporter.Reset()
+	_, _ = svc.Resolve(context.Background(), "boom", service.Visitor{})
+	assert.Equal(t, codes.Error, spansByName(exporter.GetSpans())["urlService.Resolve"].Status.Code)
+}
+
+func TestPgxTracer(t *testing.T) {
+	exporter := setupTestTracing(t)
+	tracer := tracing.PgxTracer{}
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL: "SELECT id, slug FROM urls WHERE slug = $1",
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: fmt.Errorf("scan link: %w", pgx.ErrNoRows)})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL: "\n\tUPDATE urls SET url = $2 WHERE slug = $1",
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{
+		CommandTag: pgconn.NewCommandTag("UPDATE 1"),
+		Err:        errors.New("deadlock detected"),
+	})
+
+	spans := spansByName(exporter.GetSpans())
+	require.Contains(t, spans, "postgres SELECT")
+	require.Contains(t, spans, "postgres UPDATE")
+	assert.Equal(t, codes.Unset, spans["postgres SELECT"].Status.Code, "нет строк — не сбой")
+	assert.Equal(t, codes.Error, spans["postgres UPDATE"].Status.Code)
+}
+
+func TestSetup_InvalidExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), &config.Config{TracingExporter: "jaeger"}, new(mocks.MockLogger))
+	assert.EqualError(t, err, "invalid tracing exporter: jaeger")
+}
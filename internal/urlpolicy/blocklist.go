@@ -0,0 +1,135 @@
+package urlpolicy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Thoustick/SlugKiller/pkg/logger"
+)
+
+const defaultReloadInterval = 30 * time.Second
+
+// Blocklist — список запрещённых доменов из файла: по одному домену в строке,
+// пустые строки и строки с # игнорируются. Домен блокирует и все свои поддомены.
+// Run перечитывает файл при изменении, не останавливая сервис.
+type Blocklist struct {
+	path     string
+	interval time.Duration
+	logger   logger.Logger
+
+	domains atomic.Pointer[map[string]struct{}]
+	modTime time.Time // меняется только в Load
+	size    int64
+}
+
+// NewBlocklist загружает список из файла. Ошибка чтения при старте возвращается сразу.
+func NewBlocklist(path string, interval time.Duration, log logger.Logger) (*Blocklist, error) {
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	b := &Blocklist{path: path, interval: interval, logger: log}
+	if err := b.Load(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Contains сообщает, запрещён ли host или один из его родительских доменов.
+func (b *Blocklist) Contains(host string) bool {
+	domains := *b.domains.Load()
+	for {
+		if _, ok := domains[host]; ok {
+			return true
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			return false
+		}
+		host = host[i+1:]
+	}
+}
+
+// Len возвращает число доменов в списке.
+func (b *Blocklist) Len() int {
+	return len(*b.domains.Load())
+}
+
+// Load перечитывает файл и атомарно заменяет список.
+func (b *Blocklist) Load() error {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return fmt.Errorf("open blocklist: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat blocklist: %w", err)
+	}
+
+	domains := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if d := normalizeHost(line); d != "" {
+			domains[d] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read blocklist: %w", err)
+	}
+
+	b.domains.Store(&domains)
+	b.modTime = info.ModTime()
+	b.size = info.Size()
+	return nil
+}
+
+// Run раз в interval проверяет, изменился ли файл, и перечитывает его.
+// При ошибке продолжает работать со старым списком.
+func (b *Blocklist) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.reloadIfChanged()
+		}
+	}
+}
+
+func (b *Blocklist) reloadIfChanged() {
+	info, err := os.Stat(b.path)
+	if err != nil {
+		b.logger.Warn("Failed to stat domain blocklist", map[string]interface{}{
+			"path":  b.path,
+			"error": err.Error(),
+		})
+		return
+	}
+	if info.ModTime().Equal(b.modTime) && info.Size() == b.size {
+		return
+	}
+
+	if err := b.Load(); err != nil {
+		b.logger.Error("Failed to reload domain blocklist", err, map[string]interface{}{
+			"path": b.path,
+		})
+		return
+	}
+	b.logger.Info("Domain blocklist reloaded", map[string]interface{}{
+		"path":    b.path,
+		"domains": b.Len(),
+	})
+}
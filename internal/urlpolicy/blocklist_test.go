@@ -0,0 +1,52 @@
+package urlpolicy_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/tests/mocks"
+	"github.com/Thoustick/SlugKiller/internal/urlpolicy"
+)
+
+func TestBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# phishing\nEvil.com\n\nbad.example.org  # spam\n"), 0o600))
+
+	log := new(mocks.MockLogger)
+	log.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	b, err := urlpolicy.NewBlocklist(path, 10*time.Millisecond, log)
+	require.NoError(t, err)
+	assert.Equal(t, 2, b.Len())
+	assert.True(t, b.Contains("evil.com"))
+	assert.True(t, b.Contains("login.evil.com"))
+	assert.True(t, b.Contains("bad.example.org"))
+	assert.False(t, b.Contains("example.org"))
+	assert.False(t, b.Contains("notevil.com"))
+
+	p := urlpolicy.New(defaultConfig(), b)
+	assert.Equal(t, service.URLPolicyDomainBlocked, reasonOf(p.Check(context.Background(), "https://www.evil.com/login")))
+
+	// Файл меняется на лету — воркер подхватывает новый список
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Run(ctx)
+
+	require.NoError(t, os.WriteFile(path, []byte("other.net\n"), 0o600))
+	assert.Eventually(t, func() bool { return b.Contains("other.net") }, time.Second, 10*time.Millisecond)
+	assert.False(t, b.Contains("evil.com"))
+}
+
+func TestNewBlocklist_MissingFile(t *testing.T) {
+	_, err := urlpolicy.NewBlocklist(filepath.Join(t.TempDir(), "missing.txt"), time.Second, new(mocks.MockLogger))
+
+	assert.Error(t, err)
+}
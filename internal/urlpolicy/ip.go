@@ -0,0 +1,91 @@
+package urlpolicy
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// Диапазоны, которые не входят в стандартные проверки netip, но тоже не являются публичными
+var extraPrivatePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // «этот» сетевой сегмент
+	netip.MustParsePrefix("100.64.0.0/10"), // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // бенчмарки
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 поверх IPv4
+}
+
+// isPrivate сообщает, что адрес не публичный: loopback, частные сети, link-local
+// (включая метаданные облаков 169.254.169.254), multicast и т.п.
+func isPrivate(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, prefix := range extraPrivatePrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIP распознаёт IP-литерал в хосте. Кроме канонической записи понимает формы,
+// которые браузеры тоже принимают за IPv4: 2130706433, 0x7f.1, 0177.0.0.1.
+func parseIP(host string) (netip.Addr, bool) {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return ip, true
+	}
+	return parseLooseIPv4(host)
+}
+
+func parseLooseIPv4(host string) (netip.Addr, bool) {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return netip.Addr{}, false
+	}
+
+	nums := make([]uint64, len(parts))
+	for i, part := range parts {
+		n, ok := parseIPv4Part(part)
+		if !ok {
+			return netip.Addr{}, false
+		}
+		nums[i] = n
+	}
+
+	// Последняя часть заполняет все оставшиеся байты адреса
+	last := len(nums) - 1
+	var v uint64
+	for i := 0; i < last; i++ {
+		if nums[i] > 0xff {
+			return netip.Addr{}, false
+		}
+		v |= nums[i] << (8 * (3 - i))
+	}
+	if nums[last] >= 1<<(8*(4-last)) {
+		return netip.Addr{}, false
+	}
+	v |= nums[last]
+
+	return netip.AddrFrom4([4]byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}), true
+}
+
+func parseIPv4Part(s string) (uint64, bool) {
+	base := 10
+	switch {
+	case s == "":
+		return 0, false
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		base, s = 16, s[2:]
+		if s == "" {
+			return 0, true
+		}
+	case len(s) > 1 && s[0] == '0':
+		base, s = 8, s[1:]
+	}
+	n, err := strconv.ParseUint(s, base, 32)
+	return n, err == nil
+}
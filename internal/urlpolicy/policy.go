@@ -0,0 +1,131 @@
+package urlpolicy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/service"
+)
+
+// Resolver — часть net.Resolver, нужная для проверки, куда указывает доменное имя.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// Policy проверяет адрес назначения перед сокращением: схему, хост и список запрещённых доменов.
+type Policy struct {
+	schemes      map[string]struct{}
+	blockPrivate bool
+	blockIPs     bool
+	ownDomains   []string
+	blocklist    *Blocklist // nil — список не используется
+	resolver     Resolver   // nil — доменные имена не резолвятся
+}
+
+var _ service.URLPolicy = (*Policy)(nil)
+
+// defaultSchemes используются, если список схем в конфигурации пуст
+var defaultSchemes = []string{"http", "https"}
+
+// New собирает политику из конфигурации. blocklist может быть nil.
+func New(cfg *config.Config, blocklist *Blocklist) *Policy {
+	schemes := cfg.URLAllowedSchemes
+	if len(schemes) == 0 {
+		schemes = defaultSchemes
+	}
+
+	p := &Policy{
+		schemes:      make(map[string]struct{}, len(schemes)),
+		blockPrivate: cfg.URLBlockPrivateHosts,
+		blockIPs:     cfg.URLBlockIPLiterals,
+		blocklist:    blocklist,
+	}
+	for _, scheme := range schemes {
+		p.schemes[strings.ToLower(scheme)] = struct{}{}
+	}
+	for _, d := range cfg.ShortDomains {
+		p.ownDomains = append(p.ownDomains, normalizeHost(d))
+	}
+	if cfg.URLResolveHosts {
+		p.resolver = net.DefaultResolver
+	}
+	return p
+}
+
+// WithResolver подменяет резолвер (используется в тестах).
+func (p *Policy) WithResolver(r Resolver) *Policy {
+	p.resolver = r
+	return p
+}
+
+func (p *Policy) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return violation(service.URLPolicyInvalidURL, "malformed URL")
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if _, ok := p.schemes[scheme]; !ok {
+		return violation(service.URLPolicySchemeNotAllowed, fmt.Sprintf("scheme %q is not allowed", u.Scheme))
+	}
+
+	host := normalizeHost(u.Hostname())
+	if host == "" {
+		return violation(service.URLPolicyInvalidURL, "URL has no host")
+	}
+
+	for _, own := range p.ownDomains {
+		if matchesDomain(host, own) {
+			return violation(service.URLPolicyOwnDomain, "links to the shortener itself are not allowed")
+		}
+	}
+
+	if ip, ok := parseIP(host); ok {
+		if p.blockIPs {
+			return violation(service.URLPolicyIPLiteral, "IP addresses are not allowed as a host")
+		}
+		if p.blockPrivate && isPrivate(ip) {
+			return violation(service.URLPolicyPrivateAddress, "host points to a private network")
+		}
+		return nil
+	}
+
+	if p.blockPrivate && (host == "localhost" || strings.HasSuffix(host, ".localhost")) {
+		return violation(service.URLPolicyPrivateAddress, "host points to a private network")
+	}
+
+	if p.blocklist != nil && p.blocklist.Contains(host) {
+		return violation(service.URLPolicyDomainBlocked, fmt.Sprintf("domain %q is blocked", host))
+	}
+
+	if p.blockPrivate && p.resolver != nil {
+		// Ошибку резолва не считаем нарушением: домен может временно не резолвиться
+		addrs, err := p.resolver.LookupNetIP(ctx, "ip", host)
+		if err == nil {
+			for _, addr := range addrs {
+				if isPrivate(addr) {
+					return violation(service.URLPolicyPrivateAddress, "host resolves to a private network")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func violation(reason, detail string) error {
+	return &service.URLPolicyError{Reason: reason, Detail: detail}
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// matchesDomain сообщает, совпадает ли host с domain или является его поддоменом.
+func matchesDomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,106 @@
+package urlpolicy_test
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Thoustick/SlugKiller/config"
+	"github.com/Thoustick/SlugKiller/internal/service"
+	"github.com/Thoustick/SlugKiller/internal/urlpolicy"
+)
+
+type stubResolver map[string][]netip.Addr
+
+func (r stubResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+func defaultConfig() *config.Config {
+	return &config.Config{
+		URLAllowedSchemes:    []string{"http", "https"},
+		URLBlockPrivateHosts: true,
+		ShortDomains:         []string{"sk.io"},
+	}
+}
+
+func reasonOf(err error) string {
+	var violation *service.URLPolicyError
+	if errors.As(err, &violation) {
+		return violation.Reason
+	}
+	return ""
+}
+
+func TestPolicy_Check(t *testing.T) {
+	p := urlpolicy.New(defaultConfig(), nil)
+
+	cases := []struct {
+		url    string
+		reason string
+	}{
+		{"https://example.com/path?q=1", ""},
+		{"HTTP://Example.COM", ""},
+		{"http://8.8.8.8/", ""},
+		{"javascript:alert(1)", service.URLPolicySchemeNotAllowed},
+		{"file:///etc/passwd", service.URLPolicySchemeNotAllowed},
+		{"ftp://example.com", service.URLPolicySchemeNotAllowed},
+		{"http://127.0.0.1/", service.URLPolicyPrivateAddress},
+		{"http://169.254.169.254/latest/meta-data", service.URLPolicyPrivateAddress},
+		{"http://10.1.2.3", service.URLPolicyPrivateAddress},
+		{"http://192.168.0.1:8080", service.URLPolicyPrivateAddress},
+		{"http://[::1]/", service.URLPolicyPrivateAddress},
+		{"http://[::ffff:127.0.0.1]/", service.URLPolicyPrivateAddress},
+		{"http://2130706433/", service.URLPolicyPrivateAddress},
+		{"http://0x7f.1/", service.URLPolicyPrivateAddress},
+		{"http://0177.0.0.1/", service.URLPolicyPrivateAddress},
+		{"http://localhost:8080", service.URLPolicyPrivateAddress},
+		{"http://api.localhost", service.URLPolicyPrivateAddress},
+		{"https://sk.io/abc", service.URLPolicyOwnDomain},
+		{"https://www.SK.io./abc", service.URLPolicyOwnDomain},
+		{"https:///nohost", service.URLPolicyInvalidURL},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.url, func(t *testing.T) {
+			err := p.Check(context.Background(), tc.url)
+			if tc.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tc.reason, reasonOf(err), "error: %v", err)
+		})
+	}
+}
+
+func TestPolicy_Configurable(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.URLBlockPrivateHosts = false
+	cfg.URLBlockIPLiterals = true
+	cfg.URLAllowedSchemes = []string{"https", "tg"}
+	p := urlpolicy.New(cfg, nil)
+
+	assert.NoError(t, p.Check(context.Background(), "https://localhost/"))
+	assert.NoError(t, p.Check(context.Background(), "tg://host/resolve"))
+	assert.Equal(t, service.URLPolicyIPLiteral, reasonOf(p.Check(context.Background(), "https://8.8.8.8/")))
+	assert.Equal(t, service.URLPolicySchemeNotAllowed, reasonOf(p.Check(context.Background(), "http://example.com")))
+}
+
+func TestPolicy_ResolvesHosts(t *testing.T) {
+	p := urlpolicy.New(defaultConfig(), nil).WithResolver(stubResolver{
+		"internal.example.com": {netip.MustParseAddr("10.0.0.5")},
+		"public.example.com":   {netip.MustParseAddr("93.184.216.34")},
+	})
+
+	assert.Equal(t, service.URLPolicyPrivateAddress,
+		reasonOf(p.Check(context.Background(), "https://internal.example.com")))
+	assert.NoError(t, p.Check(context.Background(), "https://public.example.com"))
+	assert.NoError(t, p.Check(context.Background(), "https://unresolvable.example.com"))
+}
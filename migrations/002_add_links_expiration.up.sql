@@ -0,0 +1,6 @@
+ALTER TABLE urls ADD COLUMN expires_at TIMESTAMPTZ NULL;
+
+-- Один и тот же URL теперь может иметь несколько ссылок (например, после истечения старой)
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS urls_url_key;
+
+CREATE INDEX idx_expires_at ON urls(expires_at) WHERE expires_at IS NOT NULL;
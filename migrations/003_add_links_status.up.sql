@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active';
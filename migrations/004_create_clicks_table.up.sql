@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS clicks (
+    id BIGSERIAL PRIMARY KEY,
+    slug VARCHAR(255) NOT NULL,
+    clicked_at TIMESTAMPTZ NOT NULL,
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_hash VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_clicks_slug_clicked_at ON clicks(slug, clicked_at);
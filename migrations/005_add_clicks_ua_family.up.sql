@@ -0,0 +1 @@
+ALTER TABLE clicks ADD COLUMN ua_family VARCHAR(64) NOT NULL DEFAULT '';
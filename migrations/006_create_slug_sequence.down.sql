@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS slug_seq;
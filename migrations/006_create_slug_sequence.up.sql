@@ -0,0 +1,2 @@
+-- Счётчик для последовательной и обфусцированной стратегий генерации slug
+CREATE SEQUENCE IF NOT EXISTS slug_seq START WITH 1;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS slug_pool;
@@ -0,0 +1,5 @@
+-- Заранее сгенерированные свободные slug; выданный slug удаляется из таблицы
+CREATE TABLE IF NOT EXISTS slug_pool (
+    slug VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
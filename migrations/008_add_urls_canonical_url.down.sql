@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_canonical_url;
+CREATE INDEX IF NOT EXISTS idx_url ON urls(url);
+
+ALTER TABLE urls DROP COLUMN IF EXISTS canonical_url;
@@ -0,0 +1,8 @@
+-- Канонический вид URL для поиска дублей. SQL не умеет нормализовать адрес, поэтому существующие ссылки
+-- получают исходный URL; после миграции запустите `go run ./cmd/canonicalize`, иначе они не найдутся как дубли.
+ALTER TABLE urls ADD COLUMN canonical_url TEXT;
+UPDATE urls SET canonical_url = url WHERE canonical_url IS NULL;
+ALTER TABLE urls ALTER COLUMN canonical_url SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_url;
+CREATE INDEX idx_canonical_url ON urls(canonical_url);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_canonical_url_owner;
+CREATE INDEX IF NOT EXISTS idx_canonical_url ON urls(canonical_url);
+
+ALTER TABLE urls DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,6 @@
+-- Владелец ссылки; пустая строка — ссылка создана анонимно
+ALTER TABLE urls ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+
+-- Дубли ищутся среди ссылок одного владельца
+DROP INDEX IF EXISTS idx_canonical_url;
+CREATE INDEX idx_canonical_url_owner ON urls(canonical_url, owner_id);
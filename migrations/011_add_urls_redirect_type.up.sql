@@ -0,0 +1,3 @@
+-- Тип перенаправления ссылки; 0 — тип по умолчанию из конфигурации сервера
+ALTER TABLE urls ADD COLUMN redirect_type SMALLINT NOT NULL DEFAULT 0
+    CHECK (redirect_type IN (0, 301, 302, 307, 308));
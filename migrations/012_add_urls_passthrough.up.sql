@@ -0,0 +1,4 @@
+-- Передача параметров запроса и хвоста пути посетителя в адрес назначения
+ALTER TABLE urls ADD COLUMN query_passthrough TEXT NOT NULL DEFAULT ''
+    CHECK (query_passthrough IN ('', 'keep', 'override', 'append'));
+ALTER TABLE urls ADD COLUMN path_passthrough BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ALTER TABLE urls DROP COLUMN IF EXISTS utm_template;
+ALTER TABLE urls DROP COLUMN IF EXISTS utm;
@@ -0,0 +1,3 @@
+-- UTM-метки, которые дописываются к адресу назначения при переходе
+ALTER TABLE urls ADD COLUMN utm JSONB;
+ALTER TABLE urls ADD COLUMN utm_template TEXT NOT NULL DEFAULT '';
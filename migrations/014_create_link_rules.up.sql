@@ -0,0 +1,12 @@
+-- Правила перехода по устройству клиента; проверяются в порядке position
+CREATE TABLE IF NOT EXISTS link_rules (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(255) NOT NULL REFERENCES urls(slug) ON DELETE CASCADE,
+    position INT NOT NULL,
+    os TEXT NOT NULL DEFAULT '',
+    device TEXT NOT NULL DEFAULT '',
+    bot BOOLEAN,
+    url TEXT NOT NULL
+);
+
+CREATE INDEX idx_link_rules_slug ON link_rules(slug, position);
@@ -0,0 +1,2 @@
+ALTER TABLE urls DROP COLUMN IF EXISTS sticky_targets;
+ALTER TABLE urls DROP COLUMN IF EXISTS targets;
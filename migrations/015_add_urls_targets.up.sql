@@ -0,0 +1,3 @@
+-- Варианты адреса назначения для A/B-разбиения: [{"variant": "a", "url": "...", "weight": 70}, ...]
+ALTER TABLE urls ADD COLUMN targets JSONB;
+ALTER TABLE urls ADD COLUMN sticky_targets BOOLEAN NOT NULL DEFAULT FALSE;
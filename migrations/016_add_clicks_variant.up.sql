@@ -0,0 +1 @@
+ALTER TABLE clicks ADD COLUMN variant VARCHAR(64) NOT NULL DEFAULT '';
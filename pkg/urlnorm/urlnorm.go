@@ -0,0 +1,111 @@
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// DefaultTrackingParams — параметры, которые не влияют на содержимое страницы.
+// Запись с * на конце задаёт префикс.
+var DefaultTrackingParams = []string{"utm_*", "fbclid", "gclid", "yclid", "mc_cid", "mc_eid"}
+
+// Options управляют тем, какие различия между адресами считаются несущественными.
+type Options struct {
+	StripFragment  bool     // отбрасывать #фрагмент
+	TrackingParams []string // параметры запроса, которые удаляются (см. DefaultTrackingParams)
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Normalize приводит URL к каноническому виду, чтобы равнозначные адреса давали одну строку:
+// схема и хост в нижнем регистре, IDN в punycode, без порта по умолчанию, пустой путь
+// заменён на "/", параметры запроса отсортированы, отслеживающие параметры удалены.
+// Результат служит ключом для поиска дублей и не используется для редиректа.
+func Normalize(raw string, opts Options) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	if u.Host != "" {
+		host, err := normalizeHost(u.Hostname())
+		if err != nil {
+			return "", err
+		}
+		port := u.Port()
+		if port == defaultPorts[u.Scheme] {
+			port = ""
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if port != "" {
+			host += ":" + port
+		}
+		u.Host = host
+
+		if u.Path == "" && u.Opaque == "" {
+			u.Path = "/"
+		}
+	}
+
+	u.RawQuery = normalizeQuery(u.RawQuery, opts.TrackingParams)
+	u.ForceQuery = false
+
+	if opts.StripFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	return u.String(), nil
+}
+
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(host, ".")
+	if ip := net.ParseIP(host); ip != nil {
+		return strings.ToLower(host), nil
+	}
+	return idna.Lookup.ToASCII(host)
+}
+
+// normalizeQuery сортирует параметры по имени (порядок значений одного параметра сохраняется)
+// и удаляет отслеживающие. Запрос, который не удаётся разобрать, остаётся как есть.
+func normalizeQuery(rawQuery string, tracking []string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return rawQuery
+	}
+	for key := range values {
+		if isTracking(key, tracking) {
+			delete(values, key)
+		}
+	}
+	return values.Encode()
+}
+
+func isTracking(key string, tracking []string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range tracking {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package urlnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	opts := Options{TrackingParams: DefaultTrackingParams}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"HTTPS://Example.com:443/a?b=1&a=2", "https://example.com/a?a=2&b=1"},
+		{"https://example.com/a?a=2&b=1", "https://example.com/a?a=2&b=1"},
+		{"http://example.com:80", "http://example.com/"},
+		{"http://example.com:8080/", "http://example.com:8080/"},
+		{"https://example.com./path", "https://example.com/path"},
+		{"https://Пример.рф/путь", "https://xn--e1afmkfd.xn--p1ai/%D0%BF%D1%83%D1%82%D1%8C"},
+		{"https://example.com/?utm_source=tg&UTM_Medium=x&fbclid=1&id=5", "https://example.com/?id=5"},
+		{"https://example.com/?a=2&a=1", "https://example.com/?a=2&a=1"},
+		{"https://example.com/page?", "https://example.com/page"},
+		{"https://example.com/page#section", "https://example.com/page#section"},
+		{"http://[2001:DB8::1]:80/", "http://[2001:db8::1]/"},
+		{"https://example.com/Path/Case", "https://example.com/Path/Case"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Normalize(tt.in, opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalize_StripFragment(t *testing.T) {
+	got, err := Normalize("https://example.com/page#section", Options{StripFragment: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/page", got)
+}
+
+func TestNormalize_Invalid(t *testing.T) {
+	_, err := Normalize("http://exa mple.com/%zz", Options{})
+
+	assert.Error(t, err)
+}
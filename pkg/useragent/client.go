@@ -0,0 +1,80 @@
+package useragent
+
+import "strings"
+
+// Семейства операционных систем, которые различает Parse
+const (
+	OSiOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSChromeOS = "chromeos"
+	OSLinux    = "linux"
+	OSOther    = "other"
+)
+
+// Классы устройств, которые различает Parse
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+)
+
+// Client — сведения о клиенте, разобранные из строки User-Agent.
+type Client struct {
+	OS     string // одно из OS*
+	Device string // одно из Device*
+	Bot    bool   // поисковый робот, HTTP-библиотека или пустой User-Agent
+}
+
+// osRule — подстрока User-Agent и соответствующая ей ОС.
+type osRule struct {
+	token string
+	os    string
+}
+
+// Порядок важен: iOS и Android упоминают "like Mac OS X" и "Linux".
+var osRules = []osRule{
+	{"iphone", OSiOS},
+	{"ipad", OSiOS},
+	{"ipod", OSiOS},
+	{"android", OSAndroid},
+	{"windows", OSWindows},
+	{"cros", OSChromeOS},
+	{"mac os x", OSMacOS},
+	{"macintosh", OSMacOS},
+	{"linux", OSLinux},
+}
+
+// Parse определяет ОС, класс устройства и признак бота по строке User-Agent.
+func Parse(ua string) Client {
+	lower := strings.ToLower(ua)
+	client := Client{OS: OSOther, Device: DeviceDesktop, Bot: isBot(ua)}
+
+	for _, rule := range osRules {
+		if strings.Contains(lower, rule.token) {
+			client.OS = rule.os
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(lower, "ipad"), strings.Contains(lower, "tablet"),
+		client.OS == OSAndroid && !strings.Contains(lower, "mobile"):
+		// Планшеты на Android не пишут "Mobile" в User-Agent
+		client.Device = DeviceTablet
+	case strings.Contains(lower, "mobi"), strings.Contains(lower, "iphone"), strings.Contains(lower, "ipod"):
+		client.Device = DeviceMobile
+	}
+	return client
+}
+
+// isBot сообщает, что запрос пришёл не от человека: от робота, HTTP-библиотеки или без User-Agent.
+func isBot(ua string) bool {
+	switch Family(ua) {
+	case FamilyBot, FamilyUnknown, "curl", "Wget", "python-requests", "Go-http-client":
+		return true
+	default:
+		return false
+	}
+}
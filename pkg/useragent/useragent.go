@@ -0,0 +1,59 @@
+package useragent
+
+import "strings"
+
+// Семейства клиентов, которые различает Family
+const (
+	FamilyUnknown = "Unknown"
+	FamilyOther   = "Other"
+	FamilyBot     = "Bot"
+)
+
+// familyRule — подстрока User-Agent и соответствующее ей семейство.
+type familyRule struct {
+	token  string
+	family string
+}
+
+// Порядок важен: Edge и Opera содержат "Chrome", Chrome содержит "Safari",
+// а ботов нужно распознать раньше браузеров, под которые они маскируются.
+var familyRules = []familyRule{
+	{"curl/", "curl"},
+	{"wget/", "Wget"},
+	{"python-requests", "python-requests"},
+	{"go-http-client", "Go-http-client"},
+	{"bot", FamilyBot},
+	{"crawler", FamilyBot},
+	{"spider", FamilyBot},
+	{"slurp", FamilyBot},
+	{"facebookexternalhit", FamilyBot},
+	{"edg/", "Edge"},
+	{"edga/", "Edge"},
+	{"edgios/", "Edge"},
+	{"opr/", "Opera"},
+	{"opera", "Opera"},
+	{"yabrowser/", "Yandex Browser"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+	{"trident/", "Internet Explorer"},
+	{"msie ", "Internet Explorer"},
+}
+
+// Family возвращает семейство клиента (браузер, бот, HTTP-библиотека) по строке User-Agent.
+func Family(ua string) string {
+	if strings.TrimSpace(ua) == "" {
+		return FamilyUnknown
+	}
+
+	lower := strings.ToLower(ua)
+	for _, rule := range familyRules {
+		if strings.Contains(lower, rule.token) {
+			return rule.family
+		}
+	}
+	return FamilyOther
+}
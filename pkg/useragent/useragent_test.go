@@ -0,0 +1,65 @@
+package useragent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Thoustick/SlugKiller/pkg/useragent"
+)
+
+func TestFamily(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36":               "Chrome",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0": "Edge",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15":            "Safari",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0":                                                        "Firefox",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/124.0 Mobile/15E148":     "Chrome",
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)":                                                      useragent.FamilyBot,
+		"curl/8.5.0":        "curl",
+		"":                  useragent.FamilyUnknown,
+		"SomethingElse/1.0": useragent.FamilyOther,
+	}
+
+	for ua, want := range cases {
+		assert.Equal(t, want, useragent.Family(ua), ua)
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]useragent.Client{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1": {
+			OS: useragent.OSiOS, Device: useragent.DeviceMobile,
+		},
+		"Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1": {
+			OS: useragent.OSiOS, Device: useragent.DeviceTablet,
+		},
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36": {
+			OS: useragent.OSAndroid, Device: useragent.DeviceMobile,
+		},
+		"Mozilla/5.0 (Linux; Android 13; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36": {
+			OS: useragent.OSAndroid, Device: useragent.DeviceTablet,
+		},
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36": {
+			OS: useragent.OSWindows, Device: useragent.DeviceDesktop,
+		},
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_4) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15": {
+			OS: useragent.OSMacOS, Device: useragent.DeviceDesktop,
+		},
+		"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36": {
+			OS: useragent.OSChromeOS, Device: useragent.DeviceDesktop,
+		},
+		"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0": {
+			OS: useragent.OSLinux, Device: useragent.DeviceDesktop,
+		},
+		"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)": {
+			OS: useragent.OSAndroid, Device: useragent.DeviceMobile, Bot: true,
+		},
+		"curl/8.5.0": {OS: useragent.OSOther, Device: useragent.DeviceDesktop, Bot: true},
+		"":           {OS: useragent.OSOther, Device: useragent.DeviceDesktop, Bot: true},
+	}
+
+	for ua, want := range cases {
+		assert.Equal(t, want, useragent.Parse(ua), ua)
+	}
+}